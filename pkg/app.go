@@ -28,8 +28,9 @@ func (ac *AppConfig) NewApp(version string) *cli.App {
 					Usage: "update db only specified distribution",
 					Value: func() *cli.StringSlice {
 						var targets cli.StringSlice
-						for _, v := range vulnsrc.All {
-							targets = append(targets, string(v.Name()))
+						ids, _ := vulnsrc.Sources()
+						for _, id := range ids {
+							targets = append(targets, string(id))
 						}
 						return &targets
 					}(),
@@ -45,6 +46,79 @@ func (ac *AppConfig) NewApp(version string) *cli.App {
 					Value:  24 * time.Hour,
 					EnvVar: "UPDATE_INTERVAL",
 				},
+				cli.BoolFlag{
+					Name:  "severity-from-cvss",
+					Usage: "derive vendor severities from CVSS scores when available",
+				},
+				cli.Int64Flag{
+					Name:  "size-budget",
+					Usage: "warn when the database file is larger than the given number of bytes (0 to disable)",
+				},
+				cli.BoolFlag{
+					Name:  "strict-size-budget",
+					Usage: "fail instead of warning when the size budget is exceeded",
+				},
+				cli.StringFlag{
+					Name:  "previous-cache-dir",
+					Usage: "cache directory of the previous build to compare record counts with",
+				},
+				cli.Float64Flag{
+					Name:  "max-record-drop",
+					Usage: "warn when a bucket loses more than the given fraction of its records compared to the previous build",
+					Value: 0.1,
+				},
+				cli.BoolFlag{
+					Name:  "strict-record-drop",
+					Usage: "fail instead of warning when the record drop is exceeded",
+				},
+			},
+		},
+		{
+			Name:   "stats",
+			Usage:  "show the composition of a database file",
+			Action: stats,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cache-dir",
+					Usage: "cache directory path",
+					Value: utils.CacheDir(),
+				},
+				cli.IntFlag{
+					Name:  "top",
+					Usage: "number of packages with the most records to show per bucket",
+					Value: 10,
+				},
+				cli.StringFlag{
+					Name:  "format",
+					Usage: "output format (table, json)",
+					Value: "table",
+				},
+			},
+		},
+		{
+			Name:      "query",
+			Usage:     "look up advisories for a package in a database file",
+			ArgsUsage: "package_name",
+			Action:    query,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "cache-dir",
+					Usage: "cache directory path",
+					Value: utils.CacheDir(),
+				},
+				cli.StringFlag{
+					Name:  "source",
+					Usage: "advisory bucket such as \"debian 12\" or \"pip::\"",
+				},
+				cli.StringFlag{
+					Name:  "arch",
+					Usage: "show only advisories that apply to the architecture",
+				},
+				cli.StringFlag{
+					Name:  "format",
+					Usage: "output format (table, json)",
+					Value: "table",
+				},
 			},
 		},
 	}
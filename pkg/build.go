@@ -6,6 +6,7 @@ import (
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/vulndb"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
 
 func build(c *cli.Context) error {
@@ -17,7 +18,20 @@ func build(c *cli.Context) error {
 	targets := c.StringSlice("only-update")
 	updateInterval := c.Duration("update-interval")
 
-	vdb := vulndb.New(cacheDir, updateInterval)
+	opts := []vulndb.Option{vulndb.WithVersion(c.App.Version)}
+	if c.Bool("severity-from-cvss") {
+		opts = append(opts, vulndb.WithVulnerabilityOptions(vulnerability.WithSeverityFromCVSS()))
+	}
+
+	if budget := c.Int64("size-budget"); budget > 0 {
+		opts = append(opts, vulndb.WithSizeBudget(budget, c.Bool("strict-size-budget")))
+	}
+
+	if prevCacheDir := c.String("previous-cache-dir"); prevCacheDir != "" {
+		opts = append(opts, vulndb.WithRegressionCheck(prevCacheDir, c.Float64("max-record-drop"), c.Bool("strict-record-drop")))
+	}
+
+	vdb := vulndb.New(cacheDir, updateInterval, opts...)
 	if err := vdb.Build(targets); err != nil {
 		return xerrors.Errorf("build error: %w", err)
 	}
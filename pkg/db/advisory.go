@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"encoding/json"
 
 	bolt "go.etcd.io/bbolt"
@@ -20,11 +21,40 @@ func (dbc Config) ForEachAdvisory(sources []string, pkgName string) (map[string]
 	return dbc.forEach(append(sources, pkgName))
 }
 
+// ForEachAdvisoryContext is like ForEachAdvisory, but stops reading when ctx is done
+func (dbc Config) ForEachAdvisoryContext(ctx context.Context, sources []string, pkgName string) (map[string]Value, error) {
+	var values map[string]Value
+	err := view(func(tx *bolt.Tx) error {
+		var err error
+		values, err = dbc.forEachTx(ctx, tx, append(sources, pkgName))
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get all key/value in the specified bucket: %w", err)
+	}
+	return values, nil
+}
+
 func (dbc Config) GetAdvisories(source, pkgName string) ([]types.Advisory, error) {
+	key := advisoryCacheKey(source, pkgName)
+	if results, ok := advisoryCache.get(key); ok {
+		return results, nil
+	}
+	gen := advisoryCache.generation()
+
 	advisories, err := dbc.ForEachAdvisory([]string{source}, pkgName)
 	if err != nil {
 		return nil, xerrors.Errorf("advisory foreach error: %w", err)
 	}
+	results, err := decodeAdvisories(advisories)
+	if err != nil {
+		return nil, err
+	}
+	advisoryCache.add(key, results, gen)
+	return results, nil
+}
+
+func decodeAdvisories(advisories map[string]Value) ([]types.Advisory, error) {
 	if len(advisories) == 0 {
 		return nil, nil
 	}
@@ -32,17 +62,14 @@ func (dbc Config) GetAdvisories(source, pkgName string) ([]types.Advisory, error
 	var results []types.Advisory
 	for vulnID, v := range advisories {
 		var advisory types.Advisory
-		if err = json.Unmarshal(v.Content, &advisory); err != nil {
+		if err := json.Unmarshal(v.Content, &advisory); err != nil {
 			return nil, xerrors.Errorf("failed to unmarshal advisory JSON: %w", err)
 		}
 
 		advisory.VulnerabilityID = vulnID
 		if v.Source != (types.DataSource{}) {
-			advisory.DataSource = &types.DataSource{
-				ID:   v.Source.ID,
-				Name: v.Source.Name,
-				URL:  v.Source.URL,
-			}
+			src := v.Source
+			advisory.DataSource = &src
 		}
 
 		results = append(results, advisory)
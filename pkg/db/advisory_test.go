@@ -1,11 +1,14 @@
 package db_test
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/dbtest"
@@ -113,7 +116,31 @@ func TestConfig_ForEachAdvisory(t *testing.T) {
 	}
 }
 
+func TestConfig_ForEachAdvisoryContext(t *testing.T) {
+	dbtest.InitDB(t, []string{"testdata/fixtures/single-bucket.yaml"})
+	defer db.Close()
+
+	dbc := db.Config{}
+	got, err := dbc.ForEachAdvisoryContext(context.Background(), []string{"GitHub Security Advisory Composer"}, "symfony/symfony")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = dbc.ForEachAdvisoryContext(ctx, []string{"GitHub Security Advisory Composer"}, "symfony/symfony")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 func TestConfig_GetAdvisories(t *testing.T) {
+	redhatSource := types.DataSource{
+		ID:        "redhat",
+		Name:      "Red Hat OVAL v2",
+		URL:       "https://www.redhat.com/security/data/oval/v2/",
+		Version:   "20240101",
+		MirrorURL: "https://mirror.example.com/oval/v2/",
+		License:   "CC-BY-4.0",
+	}
+
 	type args struct {
 		source  string
 		pkgName string
@@ -143,6 +170,29 @@ func TestConfig_GetAdvisories(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "data source with provenance",
+			args: args{
+				source:  "Red Hat Enterprise Linux 8",
+				pkgName: "bind",
+			},
+			fixtures: []string{
+				"testdata/fixtures/ospkg.yaml",
+				"testdata/fixtures/data-source.yaml",
+			},
+			want: []types.Advisory{
+				{
+					VulnerabilityID: "CVE-2018-5745",
+					FixedVersion:    "32:9.11.4-26.P2.el8",
+					DataSource:      &redhatSource,
+				},
+				{
+					VulnerabilityID: "CVE-2020-8617",
+					FixedVersion:    "32:9.11.13-5.el8_2",
+					DataSource:      &redhatSource,
+				},
+			},
+		},
 		{
 			name: "library advisories",
 			args: args{
@@ -221,3 +271,110 @@ func TestConfig_GetAdvisories(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_GetAdvisories_Cache(t *testing.T) {
+	cacheDir := dbtest.InitDB(t, []string{
+		"testdata/fixtures/ospkg.yaml",
+		"testdata/fixtures/single-bucket.yaml",
+	})
+	require.NoError(t, db.Close())
+	require.NoError(t, db.Init(cacheDir, db.WithAdvisoryCache(1)))
+	defer db.Close()
+
+	const (
+		redhat   = "Red Hat Enterprise Linux 8"
+		composer = "GitHub Security Advisory Composer"
+	)
+	dbc := db.Config{}
+	putDirect := func(source, pkgName, vulnID string) {
+		// Bypass Config so that the cache is not cleared
+		err := dbc.Connection().Update(func(tx *bolt.Tx) error {
+			return tx.Bucket([]byte(source)).Bucket([]byte(pkgName)).Put([]byte(vulnID), []byte("{}"))
+		})
+		require.NoError(t, err)
+	}
+
+	got, err := dbc.GetAdvisories(redhat, "bind")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	// Served from the cache
+	putDirect(redhat, "bind", "CVE-2024-0001")
+	got, err = dbc.GetAdvisories(redhat, "bind")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	// Evicted by another package
+	_, err = dbc.GetAdvisories(composer, "symfony/symfony")
+	require.NoError(t, err)
+	got, err = dbc.GetAdvisories(redhat, "bind")
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+
+	// Cleared by writes through Config
+	err = dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutAdvisory(tx, []string{redhat, "bind"}, "CVE-2024-0002", types.Advisory{FixedVersion: "32:9.11.36-1.el8"})
+	})
+	require.NoError(t, err)
+	got, err = dbc.GetAdvisories(redhat, "bind")
+	require.NoError(t, err)
+	assert.Len(t, got, 4)
+}
+
+func BenchmarkConfig_GetAdvisories(b *testing.B) {
+	benchmarks := []struct {
+		name      string
+		boltOpts  *bolt.Options
+		cacheSize int
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:     "16KiB pages",
+			boltOpts: &bolt.Options{PageSize: 16384},
+		},
+		{
+			name:     "map freelist",
+			boltOpts: &bolt.Options{FreelistType: bolt.FreelistMapType},
+		},
+		{
+			name:      "advisory cache",
+			cacheSize: 1000,
+		},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			require.NoError(b, db.Init(b.TempDir(), db.WithBoltOptions(bm.boltOpts), db.WithAdvisoryCache(bm.cacheSize)))
+			defer db.Close()
+
+			// 20 releases x 1,000 packages x 10 advisories, roughly the shape of an OS source
+			const releases, pkgs, advs = 20, 1000, 10
+			dbc := db.Config{}
+			for r := 0; r < releases; r++ {
+				err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+					for p := 0; p < pkgs; p++ {
+						for a := 0; a < advs; a++ {
+							bktNames := []string{fmt.Sprintf("release %d", r), fmt.Sprintf("package-%d", p)}
+							adv := types.Advisory{
+								FixedVersion: fmt.Sprintf("1.%d-%d", a, r),
+								Arches:       []string{"aarch64", "x86_64"},
+							}
+							if err := dbc.PutAdvisory(tx, bktNames, fmt.Sprintf("CVE-2024-%05d", a), adv); err != nil {
+								return err
+							}
+						}
+					}
+					return nil
+				})
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := dbc.GetAdvisories(fmt.Sprintf("release %d", i%releases), fmt.Sprintf("package-%d", i%pkgs))
+				require.NoError(b, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"io"
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+)
+
+// rename is replaced in tests to simulate a failure while swapping the database files
+var rename = os.Rename
+
+// Backup writes a consistent copy of the database to w.
+// It runs in a read transaction, so it can be called while other readers and writers are active.
+func Backup(w io.Writer) (int64, error) {
+	var n int64
+	err := view(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("failed to back up the database: %w", err)
+	}
+	return n, nil
+}
+
+// Restore replaces the database with a copy written by Backup and reopens it.
+// The copy is validated before the current database is touched.
+// It can be called while other transactions are running: swapping the database waits for running transactions,
+// including snapshots, to finish, and transactions starting in the meantime wait for the restored database.
+// It must not be called from within a transaction callback, such as the one of BatchUpdate.
+func Restore(r io.Reader) error {
+	dbMu.RLock()
+	dbPath, dir := db.Path(), dbDir
+	dbMu.RUnlock()
+
+	f, err := os.CreateTemp(dir, "restore-*.db")
+	if err != nil {
+		return xerrors.Errorf("failed to create a temp file: %w", err)
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err = io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return xerrors.Errorf("failed to write the backup: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return xerrors.Errorf("failed to close the temp file: %w", err)
+	}
+
+	// Make sure the backup is a valid bolt database
+	tmpDB, err := bolt.Open(tmpPath, 0600, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		return xerrors.Errorf("invalid backup: %w", err)
+	}
+	if err = tmpDB.View(func(tx *bolt.Tx) error {
+		// Report the first inconsistency only, but drain the channel so that the checker can finish
+		var checkErr error
+		for err := range tx.Check() {
+			if checkErr == nil {
+				checkErr = err
+			}
+		}
+		return checkErr
+	}); err != nil {
+		_ = tmpDB.Close()
+		return xerrors.Errorf("broken backup: %w", err)
+	}
+	if err = tmpDB.Close(); err != nil {
+		return xerrors.Errorf("failed to close the backup: %w", err)
+	}
+
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	if err = db.Close(); err != nil {
+		return xerrors.Errorf("failed to close DB: %w", err)
+	}
+	advisoryCache.purge()
+
+	// Keep the current database aside until the restored one is opened
+	oldPath := dbPath + ".old"
+	if err = rename(dbPath, oldPath); err != nil {
+		return reopen(dbPath, xerrors.Errorf("failed to move the current database: %w", err))
+	}
+	if err = rename(tmpPath, dbPath); err == nil {
+		if db, err = bolt.Open(dbPath, 0600, boltOptions); err == nil {
+			_ = os.Remove(oldPath)
+			return nil
+		}
+	}
+	if rerr := os.Rename(oldPath, dbPath); rerr != nil {
+		return xerrors.Errorf("failed to put back the database (%s): %v: %w", oldPath, rerr, err)
+	}
+	return reopen(dbPath, xerrors.Errorf("failed to replace the database: %w", err))
+}
+
+// reopen opens the database again after a failed Restore and returns cause
+func reopen(dbPath string, cause error) error {
+	var err error
+	if db, err = bolt.Open(dbPath, 0600, boltOptions); err != nil {
+		return xerrors.Errorf("failed to reopen db: %v: %w", err, cause)
+	}
+	return cause
+}
@@ -0,0 +1,130 @@
+package db_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestBackupRestore(t *testing.T) {
+	require.NoError(t, db.InitTemp())
+	defer db.Close()
+
+	dbc := db.Config{}
+	put := func(vulnID string) {
+		err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+			return dbc.PutAdvisory(tx, []string{"debian 12", "openssl"}, vulnID, types.Advisory{FixedVersion: "3.0.1-1"})
+		})
+		require.NoError(t, err)
+	}
+	put("CVE-2024-0001")
+
+	var buf bytes.Buffer
+	n, err := db.Backup(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	put("CVE-2024-0002")
+
+	// A broken backup must leave the database untouched
+	err = db.Restore(strings.NewReader("broken"))
+	require.ErrorContains(t, err, "invalid backup")
+	got, err := dbc.GetAdvisories("debian 12", "openssl")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	// A failure while swapping the files must leave the database open and untouched
+	reset := db.SetRename(func(oldpath, newpath string) error {
+		if strings.Contains(oldpath, "restore-") {
+			return xerrors.New("rename error")
+		}
+		return os.Rename(oldpath, newpath)
+	})
+	err = db.Restore(bytes.NewReader(buf.Bytes()))
+	reset()
+	require.ErrorContains(t, err, "rename error")
+	got, err = dbc.GetAdvisories("debian 12", "openssl")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	require.NoError(t, db.Restore(&buf))
+	got, err = dbc.GetAdvisories("debian 12", "openssl")
+	require.NoError(t, err)
+	assert.Equal(t, []types.Advisory{
+		{
+			VulnerabilityID: "CVE-2024-0001",
+			FixedVersion:    "3.0.1-1",
+		},
+	}, got)
+}
+
+func TestRestore_ConcurrentReaders(t *testing.T) {
+	require.NoError(t, db.InitTemp(db.WithAdvisoryCache(10)))
+	defer db.Close()
+
+	dbc := db.Config{}
+	err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutAdvisory(tx, []string{"debian 12", "openssl"}, "CVE-2024-0001", types.Advisory{FixedVersion: "3.0.1-1"})
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = db.Backup(&buf)
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	errCh := make(chan error, 2)
+	readers := []func() error{
+		func() error {
+			advs, err := dbc.GetAdvisories("debian 12", "openssl")
+			if err == nil && len(advs) != 1 {
+				err = xerrors.Errorf("unexpected advisories: %v", advs)
+			}
+			return err
+		},
+		func() error {
+			s, err := dbc.Snapshot()
+			if err != nil {
+				return err
+			}
+			if _, err = s.GetAdvisories("debian 12", "openssl"); err != nil {
+				_ = s.Close()
+				return err
+			}
+			return s.Close()
+		},
+	}
+	for _, read := range readers {
+		go func(read func() error) {
+			for {
+				select {
+				case <-stop:
+					errCh <- nil
+					return
+				default:
+				}
+				if err := read(); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}(read)
+	}
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, db.Restore(bytes.NewReader(buf.Bytes())))
+	}
+	close(stop)
+	for range readers {
+		assert.NoError(t, <-errCh)
+	}
+}
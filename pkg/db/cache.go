@@ -0,0 +1,108 @@
+package db
+
+import (
+	"container/list"
+	"sync"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// advisoryCache holds decoded results of GetAdvisories when enabled by WithAdvisoryCache.
+// A nil cache is valid and caches nothing.
+var advisoryCache *lruCache
+
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+
+	// gen is bumped by purge, so that results read before a write are not added afterwards
+	gen uint64
+}
+
+type lruEntry struct {
+	key        string
+	advisories []types.Advisory
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:  size,
+		ll:    list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+func advisoryCacheKey(source, pkgName string) string {
+	return source + "\x00" + pkgName
+}
+
+func (c *lruCache) get(key string) ([]types.Advisory, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return slices.Clone(e.Value.(*lruEntry).advisories), true
+}
+
+// generation returns the current generation to be passed to add
+func (c *lruCache) generation() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// add caches advisories read at generation gen, unless the cache has been purged since
+func (c *lruCache) add(key string, advisories []types.Advisory, gen uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.gen {
+		return
+	}
+
+	advisories = slices.Clone(advisories)
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*lruEntry).advisories = advisories
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{
+		key:        key,
+		advisories: advisories,
+	})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// purge drops every entry, as any write may change the advisories
+func (c *lruCache) purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = map[string]*list.Element{}
+	c.gen++
+}
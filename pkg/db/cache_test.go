@@ -0,0 +1,44 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestLRUCache(t *testing.T) {
+	advs := []types.Advisory{{VulnerabilityID: "CVE-2024-0001"}}
+
+	t.Run("eviction", func(t *testing.T) {
+		c := newLRUCache(1)
+		c.add("a", advs, c.generation())
+		c.add("b", advs, c.generation())
+
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		got, ok := c.get("b")
+		assert.True(t, ok)
+		assert.Equal(t, advs, got)
+	})
+
+	t.Run("purged while reading", func(t *testing.T) {
+		c := newLRUCache(1)
+		gen := c.generation()
+		// A write commits between the read and the fill
+		c.purge()
+		c.add("a", advs, gen)
+
+		_, ok := c.get("a")
+		assert.False(t, ok)
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var c *lruCache
+		c.add("a", advs, c.generation())
+		c.purge()
+		_, ok := c.get("a")
+		assert.False(t, ok)
+	})
+}
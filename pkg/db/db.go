@@ -2,11 +2,13 @@ package db
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"runtime/debug"
 	"strings"
+	"sync"
 
 	bolt "go.etcd.io/bbolt"
 	"golang.org/x/xerrors"
@@ -22,10 +24,21 @@ const SchemaVersion = 2
 var (
 	db    *bolt.DB
 	dbDir string
+
+	// dbMu guards db. Transactions hold it for reading, while Init, Close and Restore hold it for writing,
+	// so that the database cannot be swapped underneath a running transaction.
+	dbMu sync.RWMutex
+
+	// boltOptions is kept to reopen the database after Restore
+	boltOptions *bolt.Options
+
+	// tempDir is set when the database is opened by InitTemp and removed on Close.
+	tempDir string
 )
 
 type Operation interface {
 	BatchUpdate(fn func(*bolt.Tx) error) (err error)
+	BatchUpdateContext(ctx context.Context, fn func(*bolt.Tx) error) (err error)
 
 	GetVulnerabilityDetail(cveID string) (detail map[types.SourceID]types.VulnerabilityDetail, err error)
 	PutVulnerabilityDetail(tx *bolt.Tx, vulnerabilityID string, source types.SourceID,
@@ -33,6 +46,7 @@ type Operation interface {
 	DeleteVulnerabilityDetailBucket() (err error)
 
 	ForEachAdvisory(sources []string, pkgName string) (value map[string]Value, err error)
+	ForEachAdvisoryContext(ctx context.Context, sources []string, pkgName string) (value map[string]Value, err error)
 	GetAdvisories(source string, pkgName string) (advisories []types.Advisory, err error)
 
 	PutVulnerabilityID(tx *bolt.Tx, vulnerabilityID string) (err error)
@@ -58,7 +72,46 @@ type Operation interface {
 type Config struct {
 }
 
-func Init(cacheDir string) (err error) {
+type Options struct {
+	boltOptions       *bolt.Options
+	advisoryCacheSize int
+}
+
+type Option func(*Options)
+
+// WithBoltOptions passes options such as the page size or the freelist type to bbolt.
+// PageSize only takes effect when the database file is created.
+func WithBoltOptions(boltOpts *bolt.Options) Option {
+	return func(opts *Options) {
+		opts.boltOptions = boltOpts
+	}
+}
+
+// WithAdvisoryCache keeps up to size decoded results of GetAdvisories in memory,
+// so that repeated lookups of the same package skip JSON decoding.
+// The cache is cleared on every write through Config; writes made directly on Connection are not noticed.
+// Cached advisories share nested slices, which callers must not modify.
+func WithAdvisoryCache(size int) Option {
+	return func(opts *Options) {
+		opts.advisoryCacheSize = size
+	}
+}
+
+func Init(cacheDir string, opts ...Option) (err error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
+	dbOptions := &Options{}
+	for _, opt := range opts {
+		opt(dbOptions)
+	}
+	boltOptions = dbOptions.boltOptions
+
+	advisoryCache = nil
+	if dbOptions.advisoryCacheSize > 0 {
+		advisoryCache = newLRUCache(dbOptions.advisoryCacheSize)
+	}
+
 	dbPath := Path(cacheDir)
 	dbDir = filepath.Dir(dbPath)
 	if err = os.MkdirAll(dbDir, 0700); err != nil {
@@ -70,18 +123,58 @@ func Init(cacheDir string) (err error) {
 	debug.SetPanicOnFault(true)
 	defer func() {
 		if r := recover(); r != nil {
-			if err = os.Remove(dbPath); err != nil {
-				return
+			if boltOptions != nil && boltOptions.ReadOnly {
+				// Never remove a database that is opened only for reading
+				err = xerrors.Errorf("failed to open db: %v", r)
+			} else if err = os.Remove(dbPath); err == nil {
+				db, err = bolt.Open(dbPath, 0600, dbOptions.boltOptions)
 			}
-			db, err = bolt.Open(dbPath, 0600, nil)
 		}
 		debug.SetPanicOnFault(false)
 	}()
 
-	db, err = bolt.Open(dbPath, 0600, nil)
+	db, err = bolt.Open(dbPath, 0600, dbOptions.boltOptions)
 	if err != nil {
 		return xerrors.Errorf("failed to open db: %w", err)
 	}
+
+	if boltOptions != nil && boltOptions.ReadOnly {
+		if err = checkSize(db); err != nil {
+			_ = db.Close()
+			db = nil
+			return xerrors.Errorf("broken db: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkSize detects truncated files, which bbolt does not notice when opening them read-only.
+// Reading the missing pages later would crash the process with SIGBUS.
+func checkSize(d *bolt.DB) error {
+	info, err := os.Stat(d.Path())
+	if err != nil {
+		return xerrors.Errorf("unable to stat the database file: %w", err)
+	}
+	return d.View(func(tx *bolt.Tx) error {
+		if tx.Size() > info.Size() {
+			return xerrors.Errorf("the database file is truncated: %d bytes, expected %d bytes", info.Size(), tx.Size())
+		}
+		return nil
+	})
+}
+
+// InitTemp opens a database in a temporary directory that is removed on Close.
+// It is intended for tests and callers that only need transient lookups.
+func InitTemp(opts ...Option) error {
+	dir, err := os.MkdirTemp("", "trivy-db-*")
+	if err != nil {
+		return xerrors.Errorf("failed to create a temp dir: %w", err)
+	}
+	if err = Init(dir, opts...); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	tempDir = dir
 	return nil
 }
 
@@ -95,6 +188,9 @@ func Path(cacheDir string) string {
 }
 
 func Close() error {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+
 	// Skip closing the database if the connection is not established.
 	if db == nil {
 		return nil
@@ -102,15 +198,71 @@ func Close() error {
 	if err := db.Close(); err != nil {
 		return xerrors.Errorf("failed to close DB: %w", err)
 	}
+	advisoryCache = nil
+
+	if tempDir != "" {
+		if err := os.RemoveAll(tempDir); err != nil {
+			return xerrors.Errorf("failed to remove the temp dir: %w", err)
+		}
+		tempDir = ""
+	}
 	return nil
 }
 
+// Connection returns the underlying database.
+// Transactions started on it directly are not synchronized with Restore.
 func (dbc Config) Connection() *bolt.DB {
+	dbMu.RLock()
+	defer dbMu.RUnlock()
 	return db
 }
 
+// view runs fn in a read transaction.
+// It must not be called from within another transaction callback, as a pending Restore would deadlock it.
+func view(fn func(*bolt.Tx) error) error {
+	dbMu.RLock()
+	defer dbMu.RUnlock()
+	return db.View(fn)
+}
+
+func batch(fn func(*bolt.Tx) error) error {
+	dbMu.RLock()
+	defer dbMu.RUnlock()
+	return db.Batch(fn)
+}
+
+func update(fn func(*bolt.Tx) error) error {
+	dbMu.RLock()
+	defer dbMu.RUnlock()
+	return db.Update(fn)
+}
+
 func (dbc Config) BatchUpdate(fn func(tx *bolt.Tx) error) error {
-	err := db.Batch(fn)
+	err := batch(fn)
+	advisoryCache.purge()
+	if err != nil {
+		return xerrors.Errorf("error in batch update: %w", err)
+	}
+	return nil
+}
+
+// BatchUpdateContext is like BatchUpdate, but the transaction is rolled back
+// if ctx is done before fn runs or before the transaction is committed.
+// fn should check ctx itself for long running updates.
+func (dbc Config) BatchUpdateContext(ctx context.Context, fn func(tx *bolt.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return xerrors.Errorf("batch update canceled: %w", err)
+	}
+	err := batch(func(tx *bolt.Tx) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return ctx.Err()
+	})
+	advisoryCache.purge()
 	if err != nil {
 		return xerrors.Errorf("error in batch update: %w", err)
 	}
@@ -142,7 +294,7 @@ func (dbc Config) put(tx *bolt.Tx, bktNames []string, key string, value interfac
 }
 
 func (dbc Config) get(bktNames []string, key string) (value []byte, err error) {
-	err = db.View(func(tx *bolt.Tx) error {
+	err = view(func(tx *bolt.Tx) error {
 		if len(bktNames) == 0 {
 			return xerrors.Errorf("empty bucket name")
 		}
@@ -177,77 +329,91 @@ type Value struct {
 }
 
 func (dbc Config) forEach(bktNames []string) (map[string]Value, error) {
+	var values map[string]Value
+	err := view(func(tx *bolt.Tx) error {
+		var err error
+		values, err = dbc.forEachTx(context.Background(), tx, bktNames)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get all key/value in the specified bucket: %w", err)
+	}
+	return values, nil
+}
+
+func (dbc Config) forEachTx(ctx context.Context, tx *bolt.Tx, bktNames []string) (map[string]Value, error) {
 	if len(bktNames) < 2 {
 		return nil, xerrors.Errorf("bucket must be nested: %v", bktNames)
 	}
 	rootBucket, nestedBuckets := bktNames[0], bktNames[1:]
 
+	var rootBuckets []string
+	if strings.Contains(rootBucket, "::") {
+		// e.g. "pip::", "rubygems::"
+		prefix := []byte(rootBucket)
+		c := tx.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			rootBuckets = append(rootBuckets, string(k))
+		}
+	} else {
+		// e.g. "GitHub Security Advisory Composer"
+		rootBuckets = append(rootBuckets, rootBucket)
+	}
+
 	values := map[string]Value{}
-	err := db.View(func(tx *bolt.Tx) error {
-		var rootBuckets []string
-
-		if strings.Contains(rootBucket, "::") {
-			// e.g. "pip::", "rubygems::"
-			prefix := []byte(rootBucket)
-			c := tx.Cursor()
-			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
-				rootBuckets = append(rootBuckets, string(k))
-			}
-		} else {
-			// e.g. "GitHub Security Advisory Composer"
-			rootBuckets = append(rootBuckets, rootBucket)
+	for _, r := range rootBuckets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 
-		for _, r := range rootBuckets {
-			root := tx.Bucket([]byte(r))
-			if root == nil {
-				continue
-			}
+		root := tx.Bucket([]byte(r))
+		if root == nil {
+			continue
+		}
 
-			source, err := dbc.getDataSource(tx, r)
-			if err != nil {
-				log.Logger.Debugf("Data source error: %s", err)
-			}
+		source, err := dbc.getDataSource(tx, r)
+		if err != nil {
+			log.Logger.Debugf("Data source error: %s", err)
+		}
 
-			bkt := root
-			for _, nestedBkt := range nestedBuckets {
-				bkt = bkt.Bucket([]byte(nestedBkt))
-				if bkt == nil {
-					break
-				}
-			}
+		bkt := root
+		for _, nestedBkt := range nestedBuckets {
+			bkt = bkt.Bucket([]byte(nestedBkt))
 			if bkt == nil {
-				continue
+				break
 			}
+		}
+		if bkt == nil {
+			continue
+		}
 
-			err = bkt.ForEach(func(k, v []byte) error {
-				if len(v) == 0 {
-					return nil
-				}
-				// Copy the byte slice so it can be used outside of the current transaction
-				copiedContent := make([]byte, len(v))
-				copy(copiedContent, v)
-
-				values[string(k)] = Value{
-					Source:  source,
-					Content: copiedContent,
-				}
+		err = bkt.ForEach(func(k, v []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if len(v) == 0 {
 				return nil
-			})
-			if err != nil {
-				return xerrors.Errorf("db foreach error: %w", err)
 			}
+			// Copy the byte slice so it can be used outside of the current transaction
+			copiedContent := make([]byte, len(v))
+			copy(copiedContent, v)
+
+			values[string(k)] = Value{
+				Source:  source,
+				Content: copiedContent,
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("db foreach error: %w", err)
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, xerrors.Errorf("failed to get all key/value in the specified bucket: %w", err)
 	}
 	return values, nil
 }
 
 func (dbc Config) deleteBucket(bucketName string) error {
-	return db.Update(func(tx *bolt.Tx) error {
+	defer advisoryCache.purge()
+	return update(func(tx *bolt.Tx) error {
 		if err := tx.DeleteBucket([]byte(bucketName)); err != nil {
 			return xerrors.Errorf("failed to delete bucket: %w", err)
 		}
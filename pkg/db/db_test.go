@@ -1,20 +1,25 @@
 package db_test
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
 )
 
 func TestInit(t *testing.T) {
 	tests := []struct {
-		name   string
-		dbPath string
+		name     string
+		dbPath   string
+		readOnly bool
+		wantErr  string
 	}{
 		{
 			name:   "normal db",
@@ -28,6 +33,17 @@ func TestInit(t *testing.T) {
 			name:   "no db",
 			dbPath: "",
 		},
+		{
+			name:     "normal db, read-only",
+			dbPath:   "testdata/normal.db",
+			readOnly: true,
+		},
+		{
+			name:     "broken db, read-only",
+			dbPath:   "testdata/broken.db",
+			readOnly: true,
+			wantErr:  "the database file is truncated",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -43,12 +59,96 @@ func TestInit(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			err := db.Init(tmpDir)
+			if !tt.readOnly {
+				err := db.Init(tmpDir)
+				require.NoError(t, err)
+				return
+			}
+
+			// A read-only database must be left as it is, even if it is broken
+			err := db.Init(tmpDir, db.WithBoltOptions(&bolt.Options{ReadOnly: true}))
+			defer db.Close()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			want, err := os.ReadFile(tt.dbPath)
 			require.NoError(t, err)
+			got, err := os.ReadFile(db.Path(tmpDir))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
 		})
 	}
 }
 
+func TestInitTemp(t *testing.T) {
+	err := db.InitTemp()
+	require.NoError(t, err)
+
+	dbc := db.Config{}
+	dbPath := dbc.Connection().Path()
+	require.FileExists(t, dbPath)
+
+	err = dbc.BatchUpdate(func(tx *bolt.Tx) error {
+		return dbc.PutVulnerabilityID(tx, "CVE-2021-0001")
+	})
+	require.NoError(t, err)
+
+	err = db.Close()
+	require.NoError(t, err)
+	assert.NoDirExists(t, filepath.Dir(dbPath))
+}
+
+func TestConfig_BatchUpdateContext(t *testing.T) {
+	require.NoError(t, db.InitTemp())
+	defer db.Close()
+
+	dbc := db.Config{}
+
+	t.Run("happy path", func(t *testing.T) {
+		err := dbc.BatchUpdateContext(context.Background(), func(tx *bolt.Tx) error {
+			return dbc.PutVulnerabilityID(tx, "CVE-2021-0001")
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("canceled before start", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var called bool
+		err := dbc.BatchUpdateContext(ctx, func(tx *bolt.Tx) error {
+			called = true
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.False(t, called)
+	})
+
+	t.Run("canceled during update", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := dbc.BatchUpdateContext(ctx, func(tx *bolt.Tx) error {
+			if err := dbc.PutVulnerabilityID(tx, "CVE-2021-0002"); err != nil {
+				return err
+			}
+			cancel()
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+
+		// The transaction must be rolled back
+		var ids []string
+		err = dbc.ForEachVulnerabilityID(func(tx *bolt.Tx, cveID string) error {
+			ids = append(ids, cveID)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CVE-2021-0001"}, ids)
+	})
+}
+
 func copy(dstPath, srcPath string) error {
 	src, err := os.Open(srcPath)
 	if err != nil {
@@ -0,0 +1,10 @@
+package db
+
+// SetRename replaces the rename used by Restore and returns a function to put it back
+func SetRename(fn func(oldpath, newpath string) error) func() {
+	orig := rename
+	rename = fn
+	return func() {
+		rename = orig
+	}
+}
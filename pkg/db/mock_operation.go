@@ -3,6 +3,8 @@
 package db
 
 import (
+	context "context"
+
 	types "github.com/aquasecurity/trivy-db/pkg/types"
 	mock "github.com/stretchr/testify/mock"
 	bbolt "go.etcd.io/bbolt"
@@ -57,6 +59,57 @@ func (_m *MockOperation) BatchUpdate(fn func(*bbolt.Tx) error) error {
 	return r0
 }
 
+type OperationBatchUpdateContextArgs struct {
+	Ctx         context.Context
+	CtxAnything bool
+	Fn          func(*bbolt.Tx) error
+	FnAnything  bool
+}
+
+type OperationBatchUpdateContextReturns struct {
+	Err error
+}
+
+type OperationBatchUpdateContextExpectation struct {
+	Args    OperationBatchUpdateContextArgs
+	Returns OperationBatchUpdateContextReturns
+}
+
+func (_m *MockOperation) ApplyBatchUpdateContextExpectation(e OperationBatchUpdateContextExpectation) {
+	var args []interface{}
+	if e.Args.CtxAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Ctx)
+	}
+	if e.Args.FnAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Fn)
+	}
+	_m.On("BatchUpdateContext", args...).Return(e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyBatchUpdateContextExpectations(expectations []OperationBatchUpdateContextExpectation) {
+	for _, e := range expectations {
+		_m.ApplyBatchUpdateContextExpectation(e)
+	}
+}
+
+// BatchUpdateContext provides a mock function with given fields: ctx, fn
+func (_m *MockOperation) BatchUpdateContext(ctx context.Context, fn func(*bbolt.Tx) error) error {
+	ret := _m.Called(ctx, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(*bbolt.Tx) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type OperationDeleteAdvisoryDetailBucketReturns struct {
 	_a0 error
 }
@@ -184,6 +237,74 @@ func (_m *MockOperation) ForEachAdvisory(sources []string, pkgName string) (map[
 	return r0, r1
 }
 
+type OperationForEachAdvisoryContextArgs struct {
+	Ctx             context.Context
+	CtxAnything     bool
+	Sources         []string
+	SourcesAnything bool
+	PkgName         string
+	PkgNameAnything bool
+}
+
+type OperationForEachAdvisoryContextReturns struct {
+	Value map[string]Value
+	Err   error
+}
+
+type OperationForEachAdvisoryContextExpectation struct {
+	Args    OperationForEachAdvisoryContextArgs
+	Returns OperationForEachAdvisoryContextReturns
+}
+
+func (_m *MockOperation) ApplyForEachAdvisoryContextExpectation(e OperationForEachAdvisoryContextExpectation) {
+	var args []interface{}
+	if e.Args.CtxAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Ctx)
+	}
+	if e.Args.SourcesAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.Sources)
+	}
+	if e.Args.PkgNameAnything {
+		args = append(args, mock.Anything)
+	} else {
+		args = append(args, e.Args.PkgName)
+	}
+	_m.On("ForEachAdvisoryContext", args...).Return(e.Returns.Value, e.Returns.Err)
+}
+
+func (_m *MockOperation) ApplyForEachAdvisoryContextExpectations(expectations []OperationForEachAdvisoryContextExpectation) {
+	for _, e := range expectations {
+		_m.ApplyForEachAdvisoryContextExpectation(e)
+	}
+}
+
+// ForEachAdvisoryContext provides a mock function with given fields: ctx, sources, pkgName
+func (_m *MockOperation) ForEachAdvisoryContext(ctx context.Context, sources []string, pkgName string) (map[string]Value, error) {
+	ret := _m.Called(ctx, sources, pkgName)
+
+	var r0 map[string]Value
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) map[string]Value); ok {
+		r0 = rf(ctx, sources, pkgName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]Value)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) error); ok {
+		r1 = rf(ctx, sources, pkgName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type OperationForEachVulnerabilityIDArgs struct {
 	Fn         func(*bbolt.Tx, string) error
 	FnAnything bool
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// Snapshot is a long-lived read transaction.
+// All lookups through it observe the same state of the database, even if it is updated in the meantime,
+// so that a whole scan gets consistent results.
+//
+// The snapshot must be closed when it is no longer needed.
+// While it is open, writes that need to grow the database file, Close and Restore block.
+type Snapshot struct {
+	dbc Config
+	tx  *bolt.Tx
+}
+
+// Snapshot starts a read transaction pinning the current state of the database
+func (dbc Config) Snapshot() (*Snapshot, error) {
+	// Released by Close
+	dbMu.RLock()
+	tx, err := db.Begin(false)
+	if err != nil {
+		dbMu.RUnlock()
+		return nil, xerrors.Errorf("failed to begin a read transaction: %w", err)
+	}
+	return &Snapshot{
+		dbc: dbc,
+		tx:  tx,
+	}, nil
+}
+
+func (s *Snapshot) ForEachAdvisory(sources []string, pkgName string) (map[string]Value, error) {
+	values, err := s.dbc.forEachTx(context.Background(), s.tx, append(sources, pkgName))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get all key/value in the specified bucket: %w", err)
+	}
+	return values, nil
+}
+
+func (s *Snapshot) GetAdvisories(source, pkgName string) ([]types.Advisory, error) {
+	advisories, err := s.ForEachAdvisory([]string{source}, pkgName)
+	if err != nil {
+		return nil, xerrors.Errorf("advisory foreach error: %w", err)
+	}
+	return decodeAdvisories(advisories)
+}
+
+func (s *Snapshot) GetVulnerability(vulnID string) (types.Vulnerability, error) {
+	vuln, err := getVulnerability(s.tx, vulnID)
+	if err != nil {
+		return types.Vulnerability{}, xerrors.Errorf("failed to get the vulnerability %q: %w", vulnID, err)
+	}
+	return vuln, nil
+}
+
+// Close releases the read transaction
+func (s *Snapshot) Close() error {
+	err := s.tx.Rollback()
+	if xerrors.Is(err, bolt.ErrTxClosed) {
+		return xerrors.Errorf("failed to close the snapshot: %w", err)
+	}
+	dbMu.RUnlock()
+	if err != nil {
+		return xerrors.Errorf("failed to close the snapshot: %w", err)
+	}
+	return nil
+}
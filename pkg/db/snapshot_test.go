@@ -0,0 +1,57 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestConfig_Snapshot(t *testing.T) {
+	// Large enough not to remap, which would wait for the snapshot to be closed
+	require.NoError(t, db.InitTemp(db.WithBoltOptions(&bolt.Options{InitialMmapSize: 1 << 20})))
+	defer db.Close()
+
+	dbc := db.Config{}
+	put := func(vulnID string) {
+		err := dbc.BatchUpdate(func(tx *bolt.Tx) error {
+			if err := dbc.PutAdvisory(tx, []string{"debian 12", "openssl"}, vulnID, types.Advisory{FixedVersion: "3.0.1-1"}); err != nil {
+				return err
+			}
+			return dbc.PutVulnerability(tx, vulnID, types.Vulnerability{Title: vulnID})
+		})
+		require.NoError(t, err)
+	}
+	put("CVE-2024-0001")
+
+	snapshot, err := dbc.Snapshot()
+	require.NoError(t, err)
+
+	put("CVE-2024-0002")
+
+	got, err := snapshot.GetAdvisories("debian 12", "openssl")
+	require.NoError(t, err)
+	assert.Equal(t, []types.Advisory{
+		{
+			VulnerabilityID: "CVE-2024-0001",
+			FixedVersion:    "3.0.1-1",
+		},
+	}, got)
+
+	vuln, err := snapshot.GetVulnerability("CVE-2024-0001")
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2024-0001", vuln.Title)
+
+	_, err = snapshot.GetVulnerability("CVE-2024-0002")
+	assert.ErrorContains(t, err, "no vulnerability details for CVE-2024-0002")
+
+	require.NoError(t, snapshot.Close())
+
+	got, err = dbc.GetAdvisories("debian 12", "openssl")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
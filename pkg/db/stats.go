@@ -0,0 +1,248 @@
+package db
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// Stats describes the composition of the database
+type Stats struct {
+	FileSize   int64
+	Buckets    []BucketStats  // Root buckets sorted by name
+	Severities map[string]int // Number of vulnerabilities per severity
+}
+
+// BucketStats describes a root bucket such as "Red Hat Enterprise Linux 8" or "vulnerability"
+type BucketStats struct {
+	Name        string
+	Source      types.SourceID `json:",omitempty"` // Empty for buckets not tied to a data source
+	Size        int            // Bytes of pages in use, including nested buckets
+	Packages    int            // Nested buckets directly under the root bucket
+	Records     int            // Key/value pairs at any depth
+	TopPackages []PackageStats `json:",omitempty"`
+}
+
+type PackageStats struct {
+	Name    string
+	Records int
+}
+
+// Stats walks the whole database and reports its composition.
+// TopPackages holds up to topN packages with the most records for each bucket and is left empty when topN <= 0.
+func (dbc Config) Stats(topN int) (Stats, error) {
+	stats := Stats{
+		Severities: map[string]int{},
+	}
+	err := view(func(tx *bolt.Tx) error {
+		var err error
+		if stats.FileSize, err = fileSize(tx); err != nil {
+			return err
+		}
+
+		return tx.ForEach(func(name []byte, root *bolt.Bucket) error {
+			bs, err := bucketStats(string(name), root, topN)
+			if err != nil {
+				return xerrors.Errorf("bucket stats error: %w", err)
+			}
+			if source, err := dbc.getDataSource(tx, string(name)); err == nil {
+				bs.Source = source.ID
+			}
+			stats.Buckets = append(stats.Buckets, bs)
+
+			if string(name) != VulnerabilityBucket {
+				return nil
+			}
+			return root.ForEach(func(_, v []byte) error {
+				var vuln types.Vulnerability
+				if err := json.Unmarshal(v, &vuln); err != nil {
+					return xerrors.Errorf("failed to unmarshal JSON: %w", err)
+				}
+				severity := vuln.Severity
+				if severity == "" {
+					severity = types.SeverityUnknown.String()
+				}
+				stats.Severities[severity]++
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return Stats{}, xerrors.Errorf("failed to collect stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Sizes reports the file size and the bytes in use by each root bucket.
+// Unlike Stats, it neither counts records nor decodes vulnerabilities,
+// so only FileSize and the Name, Source and Size of buckets are filled in.
+func (dbc Config) Sizes() (Stats, error) {
+	var stats Stats
+	err := view(func(tx *bolt.Tx) error {
+		var err error
+		if stats.FileSize, err = fileSize(tx); err != nil {
+			return err
+		}
+
+		return tx.ForEach(func(name []byte, root *bolt.Bucket) error {
+			bs := BucketStats{
+				Name: string(name),
+				Size: bucketSize(root),
+			}
+			if source, err := dbc.getDataSource(tx, string(name)); err == nil {
+				bs.Source = source.ID
+			}
+			stats.Buckets = append(stats.Buckets, bs)
+			return nil
+		})
+	})
+	if err != nil {
+		return Stats{}, xerrors.Errorf("failed to collect sizes: %w", err)
+	}
+	return stats, nil
+}
+
+// Count reports the number of packages and records in each root bucket.
+// Unlike Stats, it neither measures sizes, decodes vulnerabilities nor ranks packages,
+// so it is cheap enough for health checks.
+func (dbc Config) Count() ([]BucketStats, error) {
+	var buckets []BucketStats
+	err := view(func(tx *bolt.Tx) error {
+		var err error
+		buckets, err = dbc.count(tx)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to count records: %w", err)
+	}
+	return buckets, nil
+}
+
+// CountFile is like Count, but reads the database file at dbPath instead of the opened database,
+// e.g. to compare a new build with the previous one.
+func CountFile(dbPath string) ([]BucketStats, error) {
+	fdb, err := bolt.Open(dbPath, 0600, &bolt.Options{
+		ReadOnly: true,
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open db: %w", err)
+	}
+	defer fdb.Close()
+
+	var buckets []BucketStats
+	err = fdb.View(func(tx *bolt.Tx) error {
+		var err error
+		buckets, err = Config{}.count(tx)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to count records: %w", err)
+	}
+	return buckets, nil
+}
+
+func (dbc Config) count(tx *bolt.Tx) ([]BucketStats, error) {
+	var buckets []BucketStats
+	err := tx.ForEach(func(name []byte, root *bolt.Bucket) error {
+		bs, _, err := countBucket(string(name), root, false)
+		if err != nil {
+			return xerrors.Errorf("count error: %w", err)
+		}
+		if source, err := dbc.getDataSource(tx, string(name)); err == nil {
+			bs.Source = source.ID
+		}
+		buckets = append(buckets, bs)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+func bucketStats(name string, root *bolt.Bucket, topN int) (BucketStats, error) {
+	bs, pkgs, err := countBucket(name, root, topN > 0)
+	if err != nil {
+		return BucketStats{}, err
+	}
+
+	bs.Size = bucketSize(root)
+
+	if len(pkgs) == 0 {
+		return bs, nil
+	}
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		return pkgs[i].Records > pkgs[j].Records
+	})
+	if len(pkgs) > topN {
+		pkgs = pkgs[:topN]
+	}
+	bs.TopPackages = pkgs
+
+	return bs, nil
+}
+
+func fileSize(tx *bolt.Tx) (int64, error) {
+	info, err := os.Stat(tx.DB().Path())
+	if err != nil {
+		return 0, xerrors.Errorf("unable to stat the database file: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// bucketSize returns the bytes of pages in use by the root bucket, including nested buckets
+func bucketSize(root *bolt.Bucket) int {
+	boltStats := root.Stats()
+	size := boltStats.BranchInuse + boltStats.LeafInuse
+	if size == 0 {
+		// Small buckets without nested buckets are stored inline in their parent page
+		size = boltStats.InlineBucketInuse
+	}
+	return size
+}
+
+// countBucket counts packages and records in the root bucket, listing the packages if withPkgs is true
+func countBucket(name string, root *bolt.Bucket, withPkgs bool) (BucketStats, []PackageStats, error) {
+	bs := BucketStats{Name: name}
+	var pkgs []PackageStats
+	err := root.ForEach(func(k, v []byte) error {
+		if v != nil {
+			bs.Records++
+			return nil
+		}
+		bs.Packages++
+		n := countRecords(root.Bucket(k))
+		bs.Records += n
+		if withPkgs {
+			pkgs = append(pkgs, PackageStats{
+				Name:    string(k),
+				Records: n,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return BucketStats{}, nil, xerrors.Errorf("foreach error: %w", err)
+	}
+	return bs, pkgs, nil
+}
+
+func countRecords(bkt *bolt.Bucket) int {
+	var n int
+	_ = bkt.ForEach(func(k, v []byte) error {
+		if v == nil {
+			n += countRecords(bkt.Bucket(k))
+		} else {
+			n++
+		}
+		return nil
+	})
+	return n
+}
@@ -0,0 +1,184 @@
+package db_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+)
+
+func TestConfig_Stats(t *testing.T) {
+	dbtest.InitDB(t, []string{
+		"testdata/fixtures/ospkg.yaml",
+		"testdata/fixtures/multiple-buckets.yaml",
+		"testdata/fixtures/vulnerability.yaml",
+		"testdata/fixtures/data-source.yaml",
+	})
+	defer db.Close()
+
+	got, err := db.Config{}.Stats(1)
+	require.NoError(t, err)
+
+	assert.Positive(t, got.FileSize)
+	assert.Equal(t, map[string]int{
+		"MEDIUM":  1,
+		"HIGH":    1,
+		"UNKNOWN": 1,
+	}, got.Severities)
+
+	// Sizes depend on the bolt page layout
+	for i := range got.Buckets {
+		assert.Positive(t, got.Buckets[i].Size, got.Buckets[i].Name)
+		got.Buckets[i].Size = 0
+	}
+	assert.Equal(t, []db.BucketStats{
+		{
+			Name:     "Red Hat Enterprise Linux 8",
+			Source:   "redhat",
+			Packages: 1,
+			Records:  2,
+			TopPackages: []db.PackageStats{
+				{
+					Name:    "bind",
+					Records: 2,
+				},
+			},
+		},
+		{
+			Name:     "composer::GitHub Security Advisory Composer",
+			Packages: 1,
+			Records:  1,
+			TopPackages: []db.PackageStats{
+				{
+					Name:    "symfony/symfony",
+					Records: 1,
+				},
+			},
+		},
+		{
+			Name:     "composer::php-security-advisories",
+			Packages: 1,
+			Records:  1,
+			TopPackages: []db.PackageStats{
+				{
+					Name:    "symfony/symfony",
+					Records: 1,
+				},
+			},
+		},
+		{
+			Name:    "data-source",
+			Records: 1,
+		},
+		{
+			Name:    "vulnerability",
+			Records: 3,
+		},
+	}, got.Buckets)
+}
+
+func TestConfig_Stats_NoTopPackages(t *testing.T) {
+	dbtest.InitDB(t, []string{
+		"testdata/fixtures/ospkg.yaml",
+		"testdata/fixtures/multiple-buckets.yaml",
+	})
+	defer db.Close()
+
+	for _, topN := range []int{0, -1} {
+		got, err := db.Config{}.Stats(topN)
+		require.NoError(t, err, topN)
+		for _, b := range got.Buckets {
+			assert.Positive(t, b.Records, b.Name)
+			assert.Empty(t, b.TopPackages, b.Name)
+		}
+	}
+}
+
+func TestConfig_Sizes(t *testing.T) {
+	dbtest.InitDB(t, []string{
+		"testdata/fixtures/ospkg.yaml",
+		"testdata/fixtures/vulnerability.yaml",
+		"testdata/fixtures/data-source.yaml",
+	})
+	defer db.Close()
+
+	got, err := db.Config{}.Sizes()
+	require.NoError(t, err)
+
+	stats, err := db.Config{}.Stats(0)
+	require.NoError(t, err)
+	assert.Equal(t, stats.FileSize, got.FileSize)
+	assert.Empty(t, got.Severities)
+
+	// Sizes depend on the bolt page layout, but must match Stats
+	require.Len(t, got.Buckets, len(stats.Buckets))
+	for i, b := range got.Buckets {
+		assert.Positive(t, b.Size, b.Name)
+		assert.Equal(t, stats.Buckets[i].Size, b.Size, b.Name)
+	}
+	for i := range got.Buckets {
+		got.Buckets[i].Size = 0
+	}
+	assert.Equal(t, []db.BucketStats{
+		{
+			Name:   "Red Hat Enterprise Linux 8",
+			Source: "redhat",
+		},
+		{
+			Name: "data-source",
+		},
+		{
+			Name: "vulnerability",
+		},
+	}, got.Buckets)
+}
+
+func TestConfig_Count(t *testing.T) {
+	dbtest.InitDB(t, []string{
+		"testdata/fixtures/ospkg.yaml",
+		"testdata/fixtures/vulnerability.yaml",
+		"testdata/fixtures/data-source.yaml",
+	})
+	defer db.Close()
+
+	got, err := db.Config{}.Count()
+	require.NoError(t, err)
+	assert.Equal(t, []db.BucketStats{
+		{
+			Name:     "Red Hat Enterprise Linux 8",
+			Source:   "redhat",
+			Packages: 1,
+			Records:  2,
+		},
+		{
+			Name:    "data-source",
+			Records: 1,
+		},
+		{
+			Name:    "vulnerability",
+			Records: 3,
+		},
+	}, got)
+}
+
+func TestCountFile(t *testing.T) {
+	cacheDir := dbtest.InitDB(t, []string{"testdata/fixtures/ospkg.yaml"})
+	require.NoError(t, db.Close())
+
+	got, err := db.CountFile(db.Path(cacheDir))
+	require.NoError(t, err)
+	assert.Equal(t, []db.BucketStats{
+		{
+			Name:     "Red Hat Enterprise Linux 8",
+			Packages: 1,
+			Records:  2,
+		},
+	}, got)
+
+	_, err = db.CountFile(filepath.Join(t.TempDir(), "missing.db"))
+	require.ErrorContains(t, err, "failed to open db")
+}
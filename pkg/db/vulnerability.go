@@ -10,30 +10,41 @@ import (
 )
 
 const (
-	vulnerabilityBucket = "vulnerability"
+	// VulnerabilityBucket is the root bucket holding the merged vulnerability details
+	VulnerabilityBucket = "vulnerability"
 )
 
 func (dbc Config) PutVulnerability(tx *bolt.Tx, cveID string, vuln types.Vulnerability) error {
-	if err := dbc.put(tx, []string{vulnerabilityBucket}, cveID, vuln); err != nil {
+	if err := dbc.put(tx, []string{VulnerabilityBucket}, cveID, vuln); err != nil {
 		return xerrors.Errorf("failed to put severity: %w", err)
 	}
 	return nil
 }
 
 func (dbc Config) GetVulnerability(cveID string) (vuln types.Vulnerability, err error) {
-	err = db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(vulnerabilityBucket))
-		value := bucket.Get([]byte(cveID))
-		if value == nil {
-			return xerrors.Errorf("no vulnerability details for %s", cveID)
-		}
-		if err = json.Unmarshal(value, &vuln); err != nil {
-			return xerrors.Errorf("failed to unmarshal JSON: %w", err)
-		}
-		return nil
+	err = view(func(tx *bolt.Tx) error {
+		vuln, err = getVulnerability(tx, cveID)
+		return err
 	})
 	if err != nil {
 		return types.Vulnerability{}, xerrors.Errorf("failed to get the vulnerability %q: %w", cveID, err)
 	}
 	return vuln, nil
 }
+
+func getVulnerability(tx *bolt.Tx, cveID string) (types.Vulnerability, error) {
+	bucket := tx.Bucket([]byte(VulnerabilityBucket))
+	if bucket == nil {
+		return types.Vulnerability{}, xerrors.Errorf("no such bucket: %s", VulnerabilityBucket)
+	}
+	value := bucket.Get([]byte(cveID))
+	if value == nil {
+		return types.Vulnerability{}, xerrors.Errorf("no vulnerability details for %s", cveID)
+	}
+
+	var vuln types.Vulnerability
+	if err := json.Unmarshal(value, &vuln); err != nil {
+		return types.Vulnerability{}, xerrors.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return vuln, nil
+}
@@ -18,7 +18,8 @@ func (dbc Config) PutVulnerabilityID(tx *bolt.Tx, vulnID string) error {
 }
 
 func (dbc Config) ForEachVulnerabilityID(f func(tx *bolt.Tx, vulnID string) error) error {
-	err := db.Batch(func(tx *bolt.Tx) error {
+	defer advisoryCache.purge()
+	err := batch(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(vulnerabilityIDBucket))
 		if bucket == nil {
 			return xerrors.Errorf("no such bucket: %s", vulnerabilityIDBucket)
@@ -0,0 +1,99 @@
+// Package evr parses and compares RPM epoch:version-release strings,
+// such as "1:2.3.4-alt1" or FixedVersion values of RPM-based advisories.
+package evr
+
+import (
+	"strconv"
+	"strings"
+
+	version "github.com/knqyf263/go-rpm-version"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/utils"
+)
+
+// EVR represents an RPM epoch:version-release
+type EVR struct {
+	Epoch   int
+	Version string
+	Release string
+}
+
+// Parse parses "[epoch:]version[-release]".
+// The release starts after the last '-' and may contain ':', e.g. "alt1:p10+325327.100.1.1".
+func Parse(s string) (EVR, error) {
+	var evr EVR
+	s = strings.TrimSpace(s)
+
+	// The epoch can only precede the version, i.e. the part before the release
+	vr := s
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		vr, evr.Release = s[:i], s[i+1:]
+		if evr.Release == "" {
+			return EVR{}, xerrors.Errorf("empty release in %q", s)
+		}
+	}
+
+	evr.Version = vr
+	if epoch, v, found := strings.Cut(vr, ":"); found {
+		n, err := strconv.Atoi(epoch)
+		if err != nil || n < 0 {
+			return EVR{}, xerrors.Errorf("invalid epoch in %q", s)
+		}
+		evr.Epoch, evr.Version = n, v
+	}
+
+	if evr.Version == "" {
+		return EVR{}, xerrors.Errorf("empty version in %q", s)
+	}
+	return evr, nil
+}
+
+// MustParse is like Parse but panics if the string cannot be parsed
+func MustParse(s string) EVR {
+	evr, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return evr
+}
+
+// String returns the EVR in the form FixedVersion is stored in, omitting a zero epoch
+func (e EVR) String() string {
+	return utils.ConstructVersion(strconv.Itoa(e.Epoch), e.Version, e.Release)
+}
+
+// Compare returns -1, 0 or +1 depending on whether e is older than, equal to or newer than o.
+// Versions and releases are compared with the rpmvercmp algorithm, where '~' sorts before anything,
+// so "1.0~rc1-alt1" is older than "1.0-alt1" and "alt1" is older than "alt1.p10.1".
+func (e EVR) Compare(o EVR) int {
+	switch {
+	case e.Epoch > o.Epoch:
+		return 1
+	case e.Epoch < o.Epoch:
+		return -1
+	}
+
+	if r := rpmvercmp(e.Version, o.Version); r != 0 {
+		return r
+	}
+	return rpmvercmp(e.Release, o.Release)
+}
+
+func rpmvercmp(a, b string) int {
+	// The explicit epoch keeps go-rpm-version from splitting the string at ':' or '-'
+	return version.NewVersion("0:" + a).Compare(version.NewVersion("0:" + b))
+}
+
+// Compare parses and compares two EVR strings
+func Compare(a, b string) (int, error) {
+	evrA, err := Parse(a)
+	if err != nil {
+		return 0, xerrors.Errorf("EVR parse error: %w", err)
+	}
+	evrB, err := Parse(b)
+	if err != nil {
+		return 0, xerrors.Errorf("EVR parse error: %w", err)
+	}
+	return evrA.Compare(evrB), nil
+}
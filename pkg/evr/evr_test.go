@@ -0,0 +1,131 @@
+package evr_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/evr"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    evr.EVR
+		wantErr string
+	}{
+		{
+			name:  "version and release",
+			input: "3.0.13-alt1",
+			want: evr.EVR{
+				Version: "3.0.13",
+				Release: "alt1",
+			},
+		},
+		{
+			name:  "epoch",
+			input: "1:2.35-alt1.p10.1",
+			want: evr.EVR{
+				Epoch:   1,
+				Version: "2.35",
+				Release: "alt1.p10.1",
+			},
+		},
+		{
+			name:  "release with disttag",
+			input: "0:5.10.200-alt1:p10+335731.100.1.1",
+			want: evr.EVR{
+				Version: "5.10.200",
+				Release: "alt1:p10+335731.100.1.1",
+			},
+		},
+		{
+			name:  "version only",
+			input: "1.2.3",
+			want: evr.EVR{
+				Version: "1.2.3",
+			},
+		},
+		{
+			name:    "invalid epoch",
+			input:   "x:1.0-alt1",
+			wantErr: `invalid epoch in "x:1.0-alt1"`,
+		},
+		{
+			name:    "empty release",
+			input:   "1.0-",
+			wantErr: `empty release in "1.0-"`,
+		},
+		{
+			name:    "empty version",
+			input:   "",
+			wantErr: `empty version in ""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evr.Parse(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEVR_String(t *testing.T) {
+	assert.Equal(t, "2.35-alt1", evr.MustParse("0:2.35-alt1").String())
+	assert.Equal(t, "1:2.35-alt1", evr.MustParse("1:2.35-alt1").String())
+	assert.Equal(t, "2.35", evr.MustParse("2.35").String())
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		// Real ALT version strings
+		{a: "1.1.1k-alt1", b: "1.1.1l-alt1", want: -1},
+		{a: "5.10.200-alt1", b: "5.10.199-alt1", want: 1},
+		{a: "2.35-alt1", b: "2.35-alt1.1", want: -1},
+		{a: "3.0.13-alt1", b: "3.0.13-alt1.p10.1", want: -1},
+		{a: "1.0-alt0.M80P.1", b: "1.0-alt1", want: -1},
+		{a: "115.3.0-alt0.p10.1", b: "115.3.0-alt0.p10.1", want: 0},
+		{a: "0:8.9p1-alt1", b: "8.9p1-alt1", want: 0},
+		{a: "1:2.0-alt1", b: "2.1-alt1", want: 1},
+		{a: "2.38.1-alt1:sisyphus+330436.100.1.1", b: "2.38.1-alt1:p10+330436.100.1.1", want: 1},
+
+		// Tilde sorts before anything, including the end of the string
+		{a: "1.0~rc1-alt1", b: "1.0-alt1", want: -1},
+		{a: "1.0~rc1-alt1", b: "1.0~rc2-alt1", want: -1},
+		{a: "1.0-alt1~1", b: "1.0-alt1", want: -1},
+
+		// Numbers are newer than letters and leading zeros are ignored
+		{a: "1.0a-alt1", b: "1.0.1-alt1", want: -1},
+		{a: "1.010-alt1", b: "1.10-alt1", want: 0},
+
+		// A missing release is older than any release
+		{a: "1.0", b: "1.0-alt1", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := evr.Compare(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// The comparison must be antisymmetric
+			got, err = evr.Compare(tt.b, tt.a)
+			require.NoError(t, err)
+			assert.Equal(t, -tt.want, got)
+		})
+	}
+}
+
+func TestCompare_Error(t *testing.T) {
+	_, err := evr.Compare("x:1.0-alt1", "1.0-alt1")
+	assert.ErrorContains(t, err, "EVR parse error")
+}
@@ -18,6 +18,15 @@ type Metadata struct {
 	NextUpdate   time.Time
 	UpdatedAt    time.Time
 	DownloadedAt time.Time // This field will be filled after downloading.
+	Builder      *Builder  `json:",omitempty"`
+}
+
+// Builder records what produced the database so that it can be traced back to its code and inputs
+type Builder struct {
+	Version   string            `json:",omitempty"` // trivy-db version
+	GoVersion string            `json:",omitempty"`
+	Host      string            `json:",omitempty"`
+	Inputs    map[string]string `json:",omitempty"` // e.g. vuln-list-redhat => git commit
 }
 
 // Client defines the file meta
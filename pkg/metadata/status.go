@@ -0,0 +1,51 @@
+package metadata
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// Status summarizes the last successful build of the database, e.g. for health endpoints
+type Status struct {
+	SchemaVersion   int
+	UpdatedAt       time.Time
+	NextUpdate      time.Time
+	Builder         *Builder               `json:",omitempty"`
+	Sources         map[types.SourceID]int // Number of advisories per data source
+	Vulnerabilities int                    // Number of vulnerability details
+}
+
+// Status combines the metadata with record counts of the opened database.
+// Per-source update times are not recorded, so UpdatedAt applies to every source.
+func (c Client) Status(dbc db.Config) (Status, error) {
+	meta, err := c.Get()
+	if err != nil {
+		return Status{}, xerrors.Errorf("metadata error: %w", err)
+	}
+
+	buckets, err := dbc.Count()
+	if err != nil {
+		return Status{}, xerrors.Errorf("count error: %w", err)
+	}
+
+	status := Status{
+		SchemaVersion: meta.Version,
+		UpdatedAt:     meta.UpdatedAt,
+		NextUpdate:    meta.NextUpdate,
+		Builder:       meta.Builder,
+		Sources:       map[types.SourceID]int{},
+	}
+	for _, bkt := range buckets {
+		switch {
+		case bkt.Name == db.VulnerabilityBucket:
+			status.Vulnerabilities = bkt.Records
+		case bkt.Source != "":
+			status.Sources[bkt.Source] += bkt.Records
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,72 @@
+package metadata_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func TestClient_Status(t *testing.T) {
+	builder := &metadata.Builder{
+		Version: "0.0.1",
+		Inputs:  map[string]string{"vuln-list-redhat": "0123456789abcdef0123456789abcdef01234567"},
+	}
+	tests := []struct {
+		name    string
+		meta    *metadata.Metadata
+		want    metadata.Status
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			meta: &metadata.Metadata{
+				Version:    db.SchemaVersion,
+				NextUpdate: time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
+				UpdatedAt:  time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+				Builder:    builder,
+			},
+			want: metadata.Status{
+				SchemaVersion:   db.SchemaVersion,
+				NextUpdate:      time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
+				UpdatedAt:       time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+				Builder:         builder,
+				Sources:         map[types.SourceID]int{"redhat": 2},
+				Vulnerabilities: 3,
+			},
+		},
+		{
+			name:    "no metadata",
+			wantErr: "metadata error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := dbtest.InitDB(t, []string{
+				"../db/testdata/fixtures/ospkg.yaml",
+				"../db/testdata/fixtures/vulnerability.yaml",
+				"../db/testdata/fixtures/data-source.yaml",
+			})
+			defer db.Close()
+
+			client := metadata.NewClient(cacheDir)
+			if tt.meta != nil {
+				require.NoError(t, client.Update(*tt.meta))
+			}
+
+			got, err := client.Status(db.Config{})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/lo"
+	"github.com/urfave/cli"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func query(c *cli.Context) error {
+	source, pkgName := c.String("source"), c.Args().First()
+	if source == "" || pkgName == "" {
+		return xerrors.New("both --source and a package name are required")
+	}
+
+	if err := initReadOnly(c.String("cache-dir")); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	advisories, err := db.Config{}.GetAdvisories(source, pkgName)
+	if err != nil {
+		return xerrors.Errorf("failed to get advisories: %w", err)
+	}
+
+	// Advisories without arches apply to all of them
+	if arch := c.String("arch"); arch != "" {
+		advisories = lo.Filter(advisories, func(adv types.Advisory, _ int) bool {
+			return len(adv.Arches) == 0 || slices.Contains(adv.Arches, arch)
+		})
+	}
+	sort.Slice(advisories, func(i, j int) bool {
+		return advisories[i].VulnerabilityID < advisories[j].VulnerabilityID
+	})
+
+	switch c.String("format") {
+	case "json":
+		e := json.NewEncoder(c.App.Writer)
+		e.SetIndent("", "  ")
+		return e.Encode(advisories)
+	case "table":
+		return writeAdvisories(c.App.Writer, advisories)
+	default:
+		return xerrors.Errorf("unknown format: %s", c.String("format"))
+	}
+}
+
+func writeAdvisories(out io.Writer, advisories []types.Advisory) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VULNERABILITY ID\tFIXED VERSION\tSTATUS\tARCHES")
+	for _, adv := range advisories {
+		status := ""
+		if adv.FixedVersion == "" {
+			status = adv.Status.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", adv.VulnerabilityID, adv.FixedVersion, status, strings.Join(adv.Arches, ","))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+func stats(c *cli.Context) error {
+	if c.Int("top") < 0 {
+		return xerrors.Errorf("--top must not be negative: %d", c.Int("top"))
+	}
+
+	if err := initReadOnly(c.String("cache-dir")); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	s, err := db.Config{}.Stats(c.Int("top"))
+	if err != nil {
+		return xerrors.Errorf("stats error: %w", err)
+	}
+
+	switch c.String("format") {
+	case "json":
+		e := json.NewEncoder(c.App.Writer)
+		e.SetIndent("", "  ")
+		return e.Encode(s)
+	case "table":
+		return writeStats(c.App.Writer, s)
+	default:
+		return xerrors.Errorf("unknown format: %s", c.String("format"))
+	}
+}
+
+// initReadOnly opens an existing database without modifying it
+func initReadOnly(cacheDir string) error {
+	if _, err := os.Stat(db.Path(cacheDir)); err != nil {
+		return xerrors.Errorf("database not found: %w", err)
+	}
+	if err := db.Init(cacheDir, db.WithBoltOptions(&bolt.Options{ReadOnly: true})); err != nil {
+		return xerrors.Errorf("db initialize error: %w", err)
+	}
+	return nil
+}
+
+func writeStats(out io.Writer, s db.Stats) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "File size:\t%d bytes\n\n", s.FileSize)
+
+	buckets := make([]db.BucketStats, len(s.Buckets))
+	copy(buckets, s.Buckets)
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].Size > buckets[j].Size
+	})
+	fmt.Fprintln(w, "BUCKET\tSOURCE\tSIZE\tPACKAGES\tRECORDS")
+	for _, b := range buckets {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", b.Name, b.Source, b.Size, b.Packages, b.Records)
+	}
+
+	fmt.Fprintln(w, "\nSEVERITY\tVULNERABILITIES")
+	for _, severity := range types.SeverityNames {
+		fmt.Fprintf(w, "%s\t%d\n", severity, s.Severities[severity])
+	}
+
+	fmt.Fprintln(w, "\nBUCKET\tPACKAGE\tRECORDS")
+	for _, b := range s.Buckets {
+		for _, p := range b.TopPackages {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", b.Name, p.Name, p.Records)
+		}
+	}
+	return w.Flush()
+}
@@ -93,6 +93,11 @@ type DataSource struct {
 	ID   SourceID `json:",omitempty"`
 	Name string   `json:",omitempty"`
 	URL  string   `json:",omitempty"`
+
+	// Provenance for forked or mirrored feeds
+	Version   string `json:",omitempty"` // e.g. upstream feed version or snapshot date
+	MirrorURL string `json:",omitempty"`
+	License   string `json:",omitempty"`
 }
 
 type Advisory struct {
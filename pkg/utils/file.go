@@ -1,13 +1,16 @@
 package utils
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"io"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/klauspost/compress/zstd"
 	"golang.org/x/xerrors"
 )
 
@@ -57,6 +60,8 @@ func Exists(path string) (bool, error) {
 	return true, err
 }
 
+// UnmarshalJSONFile decodes the JSON file into v.
+// Files with the ".gz" or ".zst" extension are decompressed transparently.
 func UnmarshalJSONFile(v interface{}, fileName string) error {
 	f, err := os.Open(fileName)
 	if err != nil {
@@ -64,7 +69,25 @@ func UnmarshalJSONFile(v interface{}, fileName string) error {
 	}
 	defer f.Close()
 
-	if err = json.NewDecoder(f).Decode(v); err != nil {
+	var r io.Reader = f
+	switch {
+	case strings.HasSuffix(fileName, ".gz"):
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return xerrors.Errorf("unable to decompress a file (%s): %w", fileName, err)
+		}
+		defer gr.Close()
+		r = gr
+	case strings.HasSuffix(fileName, ".zst"):
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			return xerrors.Errorf("unable to decompress a file (%s): %w", fileName, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	if err = json.NewDecoder(r).Decode(v); err != nil {
 		return xerrors.Errorf("failed to decode file (%s): %w", fileName, err)
 	}
 	return nil
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// GitRevision returns the commit checked out in the git repository at dir.
+// It reads .git directly so that the git binary is not required.
+func GitRevision(dir string) (string, error) {
+	gitDir := filepath.Join(dir, ".git")
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", xerrors.Errorf("unable to read HEAD: %w", err)
+	}
+
+	// Detached HEAD
+	ref := strings.TrimSpace(string(head))
+	if !strings.HasPrefix(ref, "ref: ") {
+		return ref, nil
+	}
+	ref = strings.TrimPrefix(ref, "ref: ")
+
+	if rev, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(rev)), nil
+	}
+
+	// The ref may only exist in packed-refs, e.g. right after cloning
+	f, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return "", xerrors.Errorf("unable to open packed-refs: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rev, name, found := strings.Cut(scanner.Text(), " ")
+		if found && name == ref {
+			return rev, nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", xerrors.Errorf("unable to read packed-refs: %w", err)
+	}
+	return "", xerrors.Errorf("no such ref: %s", ref)
+}
@@ -3,6 +3,9 @@ package strings
 import (
 	"sort"
 	"strconv"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
 )
 
 func Unique(strings []string) []string {
@@ -34,15 +37,70 @@ func InSlice(a string, list []string) bool {
 	return false
 }
 
+// Merge returns the union of a and b, sorted and without duplicates.
+// The arguments are not modified.
 func Merge(a, b []string) []string {
-	uniq := map[string]struct{}{}
-	for _, v := range append(a, b...) {
-		uniq[v] = struct{}{}
+	merged := SortedMerge(Dedupe(slices.Clone(a)), Dedupe(slices.Clone(b)))
+	if len(merged) == 0 {
+		return nil
 	}
+	return merged
+}
 
-	var merged []string
-	for u := range uniq {
-		merged = append(merged, u)
+// Dedupe sorts s and removes duplicates in place, so the result shares the backing array of s
+func Dedupe[T constraints.Ordered](s []T) []T {
+	if len(s) < 2 {
+		return s
 	}
-	return merged
+	slices.Sort(s)
+
+	n := 1
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[n-1] {
+			s[n] = s[i]
+			n++
+		}
+	}
+	return s[:n]
+}
+
+// SortedMerge merges two sorted slices without duplicates into a new sorted slice without duplicates
+func SortedMerge[T constraints.Ordered](a, b []T) []T {
+	merged := make([]T, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			merged = append(merged, a[i])
+			i++
+		case a[i] > b[j]:
+			merged = append(merged, b[j])
+			j++
+		default:
+			merged = append(merged, a[i])
+			i++
+			j++
+		}
+	}
+	merged = append(merged, a[i:]...)
+	return append(merged, b[j:]...)
+}
+
+// Intersection returns the elements present in both sorted slices without duplicates, in sorted order
+func Intersection[T constraints.Ordered](a, b []T) []T {
+	var common []T
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			common = append(common, a[i])
+			i++
+			j++
+		}
+	}
+	return common
 }
@@ -1,6 +1,7 @@
 package strings_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,3 +56,90 @@ func TestUnique(t *testing.T) {
 	}
 
 }
+
+func TestMerge(t *testing.T) {
+	a := []string{"cpe:/o:redhat:enterprise_linux:8", "cpe:/a:redhat:enterprise_linux:8::appstream"}
+	b := []string{"cpe:/o:redhat:enterprise_linux:8", "cpe:/a:redhat:enterprise_linux:8::crb"}
+
+	got := strings.Merge(a, b)
+	assert.Equal(t, []string{
+		"cpe:/a:redhat:enterprise_linux:8::appstream",
+		"cpe:/a:redhat:enterprise_linux:8::crb",
+		"cpe:/o:redhat:enterprise_linux:8",
+	}, got)
+
+	// The arguments must be left untouched
+	assert.Equal(t, "cpe:/o:redhat:enterprise_linux:8", a[0])
+	assert.Nil(t, strings.Merge(nil, nil))
+}
+
+func TestDedupe(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{
+			name:  "duplicates",
+			input: []string{"x86_64", "aarch64", "x86_64", "i586", "aarch64"},
+			want:  []string{"aarch64", "i586", "x86_64"},
+		},
+		{
+			name:  "single",
+			input: []string{"noarch"},
+			want:  []string{"noarch"},
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, strings.Dedupe(tt.input))
+		})
+	}
+}
+
+func TestSortedMerge(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3, 5, 8}, strings.SortedMerge([]int{1, 3, 5}, []int{2, 3, 8}))
+	assert.Equal(t, []string{"a", "b"}, strings.SortedMerge(nil, []string{"a", "b"}))
+	assert.Empty(t, strings.SortedMerge[string](nil, nil))
+}
+
+func TestIntersection(t *testing.T) {
+	assert.Equal(t, []int{3, 5}, strings.Intersection([]int{1, 3, 5, 7}, []int{2, 3, 5, 8}))
+	assert.Equal(t, []string{"x86_64"}, strings.Intersection([]string{"aarch64", "x86_64"}, []string{"i586", "x86_64"}))
+	assert.Nil(t, strings.Intersection([]string{"a"}, []string{"b"}))
+}
+
+func benchmarkInput(n, offset int) []string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = fmt.Sprintf("cpe:/o:example:linux:%05d", i*2+offset)
+	}
+	return s
+}
+
+func BenchmarkMerge(b *testing.B) {
+	x, y := benchmarkInput(1000, 0), benchmarkInput(1000, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		strings.Merge(x, y)
+	}
+}
+
+func BenchmarkSortedMerge(b *testing.B) {
+	x, y := benchmarkInput(1000, 0), benchmarkInput(1000, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		strings.SortedMerge(x, y)
+	}
+}
+
+func BenchmarkIntersection(b *testing.B) {
+	x, y := benchmarkInput(1000, 0), benchmarkInput(1000, 0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		strings.Intersection(x, y)
+	}
+}
@@ -1,11 +1,15 @@
 package utils
 
 import (
+	"bytes"
+	"compress/gzip"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 func touch(t *testing.T, name string) {
@@ -74,3 +78,157 @@ func TestFileWalk(t *testing.T) {
 		t.Error("The file content is wrong")
 	}
 }
+
+func TestUnmarshalJSONFile(t *testing.T) {
+	td := t.TempDir()
+
+	plain := filepath.Join(td, "tests.json")
+	write(t, plain, `{"id": "plain"}`)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write([]byte(`{"id": "gzip"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	compressed := filepath.Join(td, "tests.json.gz")
+	write(t, compressed, buf.String())
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zstdCompressed := filepath.Join(td, "definitions.json.zst")
+	write(t, zstdCompressed, string(zw.EncodeAll([]byte(`{"id": "zstd"}`), nil)))
+
+	broken := filepath.Join(td, "broken.json.gz")
+	write(t, broken, `{"id": "not compressed"}`)
+
+	brokenZstd := filepath.Join(td, "broken.json.zst")
+	write(t, brokenZstd, `{"id": "not compressed"}`)
+
+	tests := []struct {
+		name     string
+		fileName string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "plain",
+			fileName: plain,
+			want:     "plain",
+		},
+		{
+			name:     "gzip",
+			fileName: compressed,
+			want:     "gzip",
+		},
+		{
+			name:     "zstd",
+			fileName: zstdCompressed,
+			want:     "zstd",
+		},
+		{
+			name:     "broken zstd",
+			fileName: brokenZstd,
+			wantErr:  "failed to decode file",
+		},
+		{
+			name:     "broken gzip",
+			fileName: broken,
+			wantErr:  "unable to decompress a file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct{ ID string }
+			err := UnmarshalJSONFile(&got, tt.fileName)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("want error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got.ID)
+			}
+		})
+	}
+}
+
+func TestGitRevision(t *testing.T) {
+	const rev = "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "loose ref",
+			files: map[string]string{
+				"HEAD":            "ref: refs/heads/main\n",
+				"refs/heads/main": rev + "\n",
+			},
+			want: rev,
+		},
+		{
+			name: "packed ref",
+			files: map[string]string{
+				"HEAD":        "ref: refs/heads/main\n",
+				"packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" + rev + " refs/heads/main\n",
+			},
+			want: rev,
+		},
+		{
+			name: "detached HEAD",
+			files: map[string]string{
+				"HEAD": rev + "\n",
+			},
+			want: rev,
+		},
+		{
+			name: "missing ref",
+			files: map[string]string{
+				"HEAD":        "ref: refs/heads/main\n",
+				"packed-refs": rev + " refs/heads/other\n",
+			},
+			wantErr: "no such ref",
+		},
+		{
+			name:    "not a repository",
+			wantErr: "unable to read HEAD",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, ".git", filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				write(t, path, content)
+			}
+
+			got, err := GitRevision(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("want error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package vulndb
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// The number of contributors listed when the size budget is exceeded
+const topContributors = 10
+
+// checkSizeBudget compares the database file size against the budget and reports the biggest contributors.
+// Buckets of the same data source are added up, e.g. all releases of a distribution.
+func (t TrivyDB) checkSizeBudget() error {
+	stats, err := t.dbc.Sizes()
+	if err != nil {
+		return xerrors.Errorf("size error: %w", err)
+	}
+	if stats.FileSize <= t.sizeBudget {
+		return nil
+	}
+
+	sizes := map[string]int{}
+	for _, b := range stats.Buckets {
+		name := b.Name
+		if b.Source != "" {
+			name = string(b.Source)
+		}
+		sizes[name] += b.Size
+	}
+
+	var names []string
+	for name := range sizes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if sizes[names[i]] != sizes[names[j]] {
+			return sizes[names[i]] > sizes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > topContributors {
+		names = names[:topContributors]
+	}
+
+	var breakdown []string
+	for _, name := range names {
+		breakdown = append(breakdown, fmt.Sprintf("%s: %d bytes", name, sizes[name]))
+	}
+	msg := fmt.Sprintf("the database size (%d bytes) exceeds the size budget (%d bytes), biggest contributors: %s",
+		stats.FileSize, t.sizeBudget, strings.Join(breakdown, ", "))
+	if t.strictSizeBudget {
+		return xerrors.New(msg)
+	}
+	log.Println(msg)
+	return nil
+}
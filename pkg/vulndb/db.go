@@ -2,6 +2,9 @@ package vulndb
 
 import (
 	"log"
+	"os"
+	"path/filepath"
+	"runtime"
 	"time"
 
 	bolt "go.etcd.io/bbolt"
@@ -11,6 +14,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/metadata"
 	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/utils"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 )
@@ -27,6 +31,14 @@ type TrivyDB struct {
 	cacheDir       string
 	updateInterval time.Duration
 	clock          clock.Clock
+	version        string
+
+	sizeBudget       int64
+	strictSizeBudget bool
+
+	prevCacheDir     string
+	maxRecordDrop    float64
+	strictRecordDrop bool
 }
 
 type Option func(*TrivyDB)
@@ -37,6 +49,40 @@ func WithClock(clock clock.Clock) Option {
 	}
 }
 
+// WithVersion sets the builder version recorded in metadata
+func WithVersion(version string) Option {
+	return func(core *TrivyDB) {
+		core.version = version
+	}
+}
+
+// WithVulnerabilityOptions configures how vulnerability details from multiple sources are merged
+func WithVulnerabilityOptions(opts ...vulnerability.Option) Option {
+	return func(core *TrivyDB) {
+		core.vulnClient = vulnerability.New(core.dbc, opts...)
+	}
+}
+
+// WithSizeBudget checks the database size against the budget in bytes after building.
+// When the budget is exceeded, Build fails if strict is true and logs a warning otherwise.
+func WithSizeBudget(budget int64, strict bool) Option {
+	return func(core *TrivyDB) {
+		core.sizeBudget = budget
+		core.strictSizeBudget = strict
+	}
+}
+
+// WithRegressionCheck compares the number of records in each bucket with the database built under prevCacheDir.
+// When a bucket loses more than maxDrop (a fraction, e.g. 0.1 for 10%) of its records,
+// Build fails if strict is true and logs a warning otherwise.
+func WithRegressionCheck(prevCacheDir string, maxDrop float64, strict bool) Option {
+	return func(core *TrivyDB) {
+		core.prevCacheDir = prevCacheDir
+		core.maxRecordDrop = maxDrop
+		core.strictRecordDrop = strict
+	}
+}
+
 func WithVulnSrcs(srcs map[types.SourceID]vulnsrc.VulnSrc) Option {
 	return func(core *TrivyDB) {
 		core.vulnSrcs = srcs
@@ -44,11 +90,7 @@ func WithVulnSrcs(srcs map[types.SourceID]vulnsrc.VulnSrc) Option {
 }
 
 func New(cacheDir string, updateInterval time.Duration, opts ...Option) *TrivyDB {
-	// Initialize map
-	vulnSrcs := map[types.SourceID]vulnsrc.VulnSrc{}
-	for _, v := range vulnsrc.All {
-		vulnSrcs[v.Name()] = v
-	}
+	_, vulnSrcs := vulnsrc.Sources()
 
 	dbc := db.Config{}
 	tdb := &TrivyDB{
@@ -86,6 +128,7 @@ func (t TrivyDB) Insert(targets []string) error {
 		Version:    db.SchemaVersion,
 		NextUpdate: t.clock.Now().UTC().Add(t.updateInterval),
 		UpdatedAt:  t.clock.Now().UTC(),
+		Builder:    t.builder(),
 	}
 
 	if err := t.metadata.Update(md); err != nil {
@@ -95,6 +138,36 @@ func (t TrivyDB) Insert(targets []string) error {
 	return nil
 }
 
+func (t TrivyDB) builder() *metadata.Builder {
+	b := &metadata.Builder{
+		Version:   t.version,
+		GoVersion: runtime.Version(),
+	}
+	if host, err := os.Hostname(); err == nil {
+		b.Host = host
+	}
+
+	// Sources read their vuln-list repositories from the cache dir
+	entries, err := os.ReadDir(t.cacheDir)
+	if err != nil {
+		return b
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		rev, err := utils.GitRevision(filepath.Join(t.cacheDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if b.Inputs == nil {
+			b.Inputs = map[string]string{}
+		}
+		b.Inputs[entry.Name()] = rev
+	}
+	return b
+}
+
 func (t TrivyDB) Build(targets []string) error {
 	// Insert all security advisories
 	if err := t.Insert(targets); err != nil {
@@ -111,16 +184,24 @@ func (t TrivyDB) Build(targets []string) error {
 		return xerrors.Errorf("cleanup error: %w", err)
 	}
 
+	if t.sizeBudget > 0 {
+		if err := t.checkSizeBudget(); err != nil {
+			return xerrors.Errorf("size budget error: %w", err)
+		}
+	}
+
+	if t.prevCacheDir != "" {
+		if err := t.checkRegression(); err != nil {
+			return xerrors.Errorf("regression check error: %w", err)
+		}
+	}
+
 	return nil
 }
 
 func (t TrivyDB) vulnSrc(target string) (vulnsrc.VulnSrc, bool) {
-	for _, src := range t.vulnSrcs {
-		if target == string(src.Name()) {
-			return src, true
-		}
-	}
-	return nil, false
+	src, ok := t.vulnSrcs[types.SourceID(target)]
+	return src, ok
 }
 
 func (t TrivyDB) optimize() error {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -35,6 +36,9 @@ func (f fakeVulnSrc) Update(dir string) error {
 }
 
 func TestTrivyDB_Insert(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
 	type fields struct {
 		cacheDir string
 		clock    clock.Clock
@@ -62,6 +66,14 @@ func TestTrivyDB_Insert(t *testing.T) {
 				Version:    db.SchemaVersion,
 				NextUpdate: time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
 				UpdatedAt:  time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+				Builder: &metadata.Builder{
+					Version:   "0.0.1",
+					GoVersion: runtime.Version(),
+					Host:      hostname,
+					Inputs: map[string]string{
+						"vuln-list": "0123456789abcdef0123456789abcdef01234567",
+					},
+				},
 			},
 		},
 		{
@@ -98,8 +110,15 @@ func TestTrivyDB_Insert(t *testing.T) {
 			require.NoError(t, db.Init(cacheDir))
 			defer db.Close()
 
-			c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithClock(tt.fields.clock), vulndb.WithVulnSrcs(vulnsrcs))
-			err := c.Insert(tt.args.targets)
+			// Fake vuln-list checkout
+			gitDir := filepath.Join(cacheDir, "vuln-list", ".git")
+			require.NoError(t, os.MkdirAll(gitDir, 0700))
+			err := os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("0123456789abcdef0123456789abcdef01234567\n"), 0600)
+			require.NoError(t, err)
+
+			c := vulndb.New(cacheDir, 12*time.Hour, vulndb.WithClock(tt.fields.clock), vulndb.WithVulnSrcs(vulnsrcs),
+				vulndb.WithVersion("0.0.1"))
+			err = c.Insert(tt.args.targets)
 			if tt.wantErr != "" {
 				require.NotNil(t, err)
 				assert.Contains(t, err.Error(), tt.wantErr)
@@ -128,9 +147,16 @@ func TestTrivyDB_Build(t *testing.T) {
 		key   []string
 		value interface{}
 	}
+	type regression struct {
+		fixtures []string
+		maxDrop  float64
+		strict   bool
+	}
 	tests := []struct {
 		name       string
 		fixtures   []string
+		opts       []vulndb.Option
+		regression *regression
 		wantValues []wantKV
 		wantErr    string
 	}{
@@ -164,6 +190,88 @@ func TestTrivyDB_Build(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "size budget exceeded, warning",
+			fixtures: []string{
+				"testdata/fixtures/happy/vulnid.yaml",
+				"testdata/fixtures/happy/vulnerability-detail.yaml",
+				"testdata/fixtures/happy/advisory-detail.yaml",
+			},
+			opts: []vulndb.Option{vulndb.WithSizeBudget(1, false)},
+			wantValues: []wantKV{
+				{
+					key: []string{"Red Hat Enterprise Linux 8", "python-jinja2", "CVE-2019-10906"},
+					value: types.Advisory{
+						FixedVersion: "2.10.1-2.el8_0",
+					},
+				},
+			},
+		},
+		{
+			name: "size budget exceeded, strict",
+			fixtures: []string{
+				"testdata/fixtures/happy/vulnid.yaml",
+				"testdata/fixtures/happy/vulnerability-detail.yaml",
+				"testdata/fixtures/happy/advisory-detail.yaml",
+			},
+			opts:    []vulndb.Option{vulndb.WithSizeBudget(1, true)},
+			wantErr: "exceeds the size budget (1 bytes), biggest contributors: vulnerability:",
+		},
+		{
+			name: "record drop within the threshold",
+			fixtures: []string{
+				"testdata/fixtures/happy/vulnid.yaml",
+				"testdata/fixtures/happy/vulnerability-detail.yaml",
+				"testdata/fixtures/happy/advisory-detail.yaml",
+			},
+			regression: &regression{
+				fixtures: []string{"testdata/fixtures/previous/advisory.yaml"},
+				maxDrop:  0.5,
+				strict:   true,
+			},
+			wantValues: []wantKV{
+				{
+					key: []string{"Red Hat Enterprise Linux 8", "python-jinja2", "CVE-2019-10906"},
+					value: types.Advisory{
+						FixedVersion: "2.10.1-2.el8_0",
+					},
+				},
+			},
+		},
+		{
+			name: "record drop exceeded, warning",
+			fixtures: []string{
+				"testdata/fixtures/happy/vulnid.yaml",
+				"testdata/fixtures/happy/vulnerability-detail.yaml",
+				"testdata/fixtures/happy/advisory-detail.yaml",
+			},
+			regression: &regression{
+				fixtures: []string{"testdata/fixtures/previous/advisory.yaml"},
+				maxDrop:  0.1,
+			},
+			wantValues: []wantKV{
+				{
+					key: []string{"Red Hat Enterprise Linux 8", "python-jinja2", "CVE-2019-10906"},
+					value: types.Advisory{
+						FixedVersion: "2.10.1-2.el8_0",
+					},
+				},
+			},
+		},
+		{
+			name: "record drop exceeded, strict",
+			fixtures: []string{
+				"testdata/fixtures/happy/vulnid.yaml",
+				"testdata/fixtures/happy/vulnerability-detail.yaml",
+				"testdata/fixtures/happy/advisory-detail.yaml",
+			},
+			regression: &regression{
+				fixtures: []string{"testdata/fixtures/previous/advisory.yaml"},
+				maxDrop:  0.1,
+				strict:   true,
+			},
+			wantErr: "dropped by more than 10% from the previous database: Red Hat Enterprise Linux 8: 2 -> 1",
+		},
 		{
 			name: "broken advisory detail",
 			fixtures: []string{
@@ -185,10 +293,17 @@ func TestTrivyDB_Build(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			if tt.regression != nil {
+				prevCacheDir := dbtest.InitDB(t, tt.regression.fixtures)
+				require.NoError(t, db.Close())
+				opts = append(opts, vulndb.WithRegressionCheck(prevCacheDir, tt.regression.maxDrop, tt.regression.strict))
+			}
+
 			cacheDir := dbtest.InitDB(t, tt.fixtures)
 			defer db.Close()
 
-			full := vulndb.New(cacheDir, 12*time.Hour)
+			full := vulndb.New(cacheDir, 12*time.Hour, opts...)
 			err := full.Build(nil)
 			if tt.wantErr != "" {
 				require.NotNil(t, err)
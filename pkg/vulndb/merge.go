@@ -0,0 +1,230 @@
+package vulndb
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	ustrings "github.com/aquasecurity/trivy-db/pkg/utils/strings"
+)
+
+// mergeOpenTimeout bounds the wait for the file lock of a source database that is still opened
+const mergeOpenTimeout = time.Second
+
+// Merge combines the databases built under srcDirs into a new database under dstDir,
+// e.g. the upstream trivy-db and a build containing only additional sources.
+// Sources are applied in order and the first one wins when the same advisory or data source exists in several,
+// while vulnerability details are merged field by field so that vendor severities and CVSS from every source are kept.
+// The source databases must not be opened by db.Init during the merge, otherwise Merge fails.
+// The destination is removed when the merge fails, so that it can be retried.
+func Merge(dstDir string, srcDirs ...string) (err error) {
+	if len(srcDirs) == 0 {
+		return xerrors.New("no database to merge")
+	}
+
+	var metas []metadata.Metadata
+	for _, dir := range srcDirs {
+		meta, err := metadata.NewClient(dir).Get()
+		if err != nil {
+			return xerrors.Errorf("failed to get the metadata of %s: %w", dir, err)
+		}
+		if meta.Version != db.SchemaVersion {
+			return xerrors.Errorf("%s: unsupported schema version %d, expected %d", dir, meta.Version, db.SchemaVersion)
+		}
+		metas = append(metas, meta)
+	}
+
+	dstPath := db.Path(dstDir)
+	if _, err := os.Stat(dstPath); err == nil {
+		return xerrors.Errorf("%s already exists", dstPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0700); err != nil {
+		return xerrors.Errorf("failed to mkdir: %w", err)
+	}
+
+	dst, err := bolt.Open(dstPath, 0600, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to open db: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(dstPath)
+		}
+	}()
+
+	for _, dir := range srcDirs {
+		if err = mergeDB(dst, db.Path(dir)); err != nil {
+			return xerrors.Errorf("failed to merge %s: %w", dir, err)
+		}
+	}
+	if err = dst.Close(); err != nil {
+		return xerrors.Errorf("failed to close db: %w", err)
+	}
+
+	if err = metadata.NewClient(dstDir).Update(mergeMetadata(metas)); err != nil {
+		return xerrors.Errorf("metadata update error: %w", err)
+	}
+	return nil
+}
+
+func mergeDB(dst *bolt.DB, srcPath string) error {
+	src, err := bolt.Open(srcPath, 0600, &bolt.Options{
+		ReadOnly: true,
+		Timeout:  mergeOpenTimeout,
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to open db: %w", err)
+	}
+	defer src.Close()
+
+	// Values read from the source are only valid during its transaction,
+	// so the destination transaction must be committed within it.
+	return src.View(func(stx *bolt.Tx) error {
+		return dst.Update(func(dtx *bolt.Tx) error {
+			return stx.ForEach(func(name []byte, srcBkt *bolt.Bucket) error {
+				dstBkt, err := dtx.CreateBucketIfNotExists(name)
+				if err != nil {
+					return xerrors.Errorf("failed to create %s bucket: %w", name, err)
+				}
+
+				var resolve func(cur, v []byte) ([]byte, error)
+				if string(name) == db.VulnerabilityBucket {
+					resolve = mergeVulnerability
+				}
+				if err = mergeBucket(dstBkt, srcBkt, resolve); err != nil {
+					return xerrors.Errorf("%s: %w", name, err)
+				}
+				return nil
+			})
+		})
+	})
+}
+
+// mergeBucket copies src into dst recursively.
+// Existing values in dst are kept unless resolve is given.
+func mergeBucket(dst, src *bolt.Bucket, resolve func(cur, v []byte) ([]byte, error)) error {
+	return src.ForEach(func(k, v []byte) error {
+		// nested bucket
+		if v == nil {
+			child, err := dst.CreateBucketIfNotExists(k)
+			if err != nil {
+				return xerrors.Errorf("failed to create %s bucket: %w", k, err)
+			}
+			return mergeBucket(child, src.Bucket(k), resolve)
+		}
+
+		cur := dst.Get(k)
+		switch {
+		case cur == nil:
+			return dst.Put(k, v)
+		case resolve == nil:
+			return nil
+		}
+
+		merged, err := resolve(cur, v)
+		if err != nil {
+			return xerrors.Errorf("failed to merge %s: %w", k, err)
+		}
+		return dst.Put(k, merged)
+	})
+}
+
+func mergeVulnerability(cur, v []byte) ([]byte, error) {
+	var a, b types.Vulnerability
+	if err := json.Unmarshal(cur, &a); err != nil {
+		return nil, xerrors.Errorf("json unmarshal error: %w", err)
+	}
+	if err := json.Unmarshal(v, &b); err != nil {
+		return nil, xerrors.Errorf("json unmarshal error: %w", err)
+	}
+
+	if a.Title == "" {
+		a.Title = b.Title
+	}
+	if a.Description == "" {
+		a.Description = b.Description
+	}
+	if a.Severity == "" {
+		a.Severity = b.Severity
+	}
+	if a.PublishedDate == nil {
+		a.PublishedDate = b.PublishedDate
+	}
+	if a.LastModifiedDate == nil {
+		a.LastModifiedDate = b.LastModifiedDate
+	}
+	if a.Custom == nil {
+		a.Custom = b.Custom
+	}
+	a.CweIDs = ustrings.Merge(a.CweIDs, b.CweIDs)
+	a.References = ustrings.Merge(a.References, b.References)
+
+	for id, severity := range b.VendorSeverity {
+		if a.VendorSeverity == nil {
+			a.VendorSeverity = types.VendorSeverity{}
+		}
+		if _, ok := a.VendorSeverity[id]; !ok {
+			a.VendorSeverity[id] = severity
+		}
+	}
+	for id, cvss := range b.CVSS {
+		if a.CVSS == nil {
+			a.CVSS = types.VendorCVSS{}
+		}
+		if _, ok := a.CVSS[id]; !ok {
+			a.CVSS[id] = cvss
+		}
+	}
+
+	merged, err := json.Marshal(a)
+	if err != nil {
+		return nil, xerrors.Errorf("json marshal error: %w", err)
+	}
+	return merged, nil
+}
+
+// mergeMetadata takes the oldest UpdatedAt and the earliest NextUpdate,
+// as the merged database is only as fresh as its oldest input.
+// The builder is taken from the first database that records one, with the inputs of all databases.
+func mergeMetadata(metas []metadata.Metadata) metadata.Metadata {
+	merged := metadata.Metadata{
+		Version:    db.SchemaVersion,
+		NextUpdate: metas[0].NextUpdate,
+		UpdatedAt:  metas[0].UpdatedAt,
+	}
+	for _, meta := range metas {
+		if meta.NextUpdate.Before(merged.NextUpdate) {
+			merged.NextUpdate = meta.NextUpdate
+		}
+		if meta.UpdatedAt.Before(merged.UpdatedAt) {
+			merged.UpdatedAt = meta.UpdatedAt
+		}
+		if meta.Builder == nil {
+			continue
+		}
+		if merged.Builder == nil {
+			merged.Builder = &metadata.Builder{
+				Version:   meta.Builder.Version,
+				GoVersion: meta.Builder.GoVersion,
+				Host:      meta.Builder.Host,
+			}
+		}
+		for name, rev := range meta.Builder.Inputs {
+			if merged.Builder.Inputs == nil {
+				merged.Builder.Inputs = map[string]string{}
+			}
+			if _, ok := merged.Builder.Inputs[name]; !ok {
+				merged.Builder.Inputs[name] = rev
+			}
+		}
+	}
+	return merged
+}
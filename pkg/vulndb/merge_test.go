@@ -0,0 +1,203 @@
+package vulndb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/dbtest"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulndb"
+)
+
+func TestMerge(t *testing.T) {
+	type src struct {
+		fixtures []string
+		meta     metadata.Metadata
+		keepOpen bool
+	}
+	type wantKV struct {
+		key   []string
+		value interface{}
+	}
+	tests := []struct {
+		name       string
+		srcs       []src
+		wantMeta   metadata.Metadata
+		wantValues []wantKV
+		wantErr    string
+	}{
+		{
+			name: "happy path",
+			srcs: []src{
+				{
+					fixtures: []string{"testdata/fixtures/merge/upstream.yaml"},
+					meta: metadata.Metadata{
+						Version:    db.SchemaVersion,
+						NextUpdate: time.Date(2021, 1, 2, 15, 0, 0, 0, time.UTC),
+						UpdatedAt:  time.Date(2021, 1, 2, 3, 0, 0, 0, time.UTC),
+						Builder: &metadata.Builder{
+							Version:   "0.0.1",
+							GoVersion: "go1.19",
+							Host:      "builder-1",
+							Inputs:    map[string]string{"vuln-list": "aaaa"},
+						},
+					},
+				},
+				{
+					fixtures: []string{"testdata/fixtures/merge/extra.yaml"},
+					meta: metadata.Metadata{
+						Version:    db.SchemaVersion,
+						NextUpdate: time.Date(2021, 1, 2, 9, 0, 0, 0, time.UTC),
+						UpdatedAt:  time.Date(2021, 1, 2, 6, 0, 0, 0, time.UTC),
+						Builder: &metadata.Builder{
+							Version: "0.0.2",
+							Host:    "builder-2",
+							Inputs: map[string]string{
+								"vuln-list":     "bbbb",
+								"vuln-list-alt": "cccc",
+							},
+						},
+					},
+				},
+			},
+			wantMeta: metadata.Metadata{
+				Version:    db.SchemaVersion,
+				NextUpdate: time.Date(2021, 1, 2, 9, 0, 0, 0, time.UTC),
+				UpdatedAt:  time.Date(2021, 1, 2, 3, 0, 0, 0, time.UTC),
+				Builder: &metadata.Builder{
+					Version:   "0.0.1",
+					GoVersion: "go1.19",
+					Host:      "builder-1",
+					Inputs: map[string]string{
+						"vuln-list":     "aaaa",
+						"vuln-list-alt": "cccc",
+					},
+				},
+			},
+			wantValues: []wantKV{
+				{
+					key: []string{"Red Hat Enterprise Linux 8", "python-jinja2", "CVE-2019-10906"},
+					value: types.Advisory{
+						FixedVersion: "2.10.1-2.el8_0",
+					},
+				},
+				{
+					key: []string{"ALT Linux p10", "python3-module-jinja2", "CVE-2019-10906"},
+					value: types.Advisory{
+						FixedVersion: "2.10.1-alt1",
+					},
+				},
+				{
+					key: []string{"data-source", "Red Hat Enterprise Linux 8"},
+					value: types.DataSource{
+						ID:   "redhat",
+						Name: "Red Hat OVAL v2",
+						URL:  "https://www.redhat.com/security/data/oval/v2/",
+					},
+				},
+				{
+					key: []string{"data-source", "ALT Linux p10"},
+					value: types.DataSource{
+						ID:   "alt",
+						Name: "ALT Linux OVAL",
+						URL:  "https://rdb.altlinux.org/api/errata/export/oval",
+					},
+				},
+				{
+					key: []string{"vulnerability", "CVE-2019-10906"},
+					value: types.Vulnerability{
+						Title:       "python-jinja2: str.format_map allows sandbox escape",
+						Description: "In Pallets Jinja before 2.10.1, str.format_map allows a sandbox escape.",
+						CweIDs:      []string{"CWE-20", "CWE-693"},
+						VendorSeverity: types.VendorSeverity{
+							"nvd":    types.SeverityHigh,
+							"redhat": types.SeverityCritical,
+							"alt":    types.SeverityHigh,
+						},
+						References: []string{
+							"https://bugzilla.altlinux.org/12345",
+							"https://nvd.nist.gov/vuln/detail/CVE-2019-10906",
+						},
+					},
+				},
+				{
+					key: []string{"vulnerability", "CVE-2024-0001"},
+					value: types.Vulnerability{
+						Title: "only in the extra database",
+					},
+				},
+			},
+		},
+		{
+			name: "schema version mismatch",
+			srcs: []src{
+				{
+					fixtures: []string{"testdata/fixtures/merge/upstream.yaml"},
+					meta:     metadata.Metadata{Version: db.SchemaVersion},
+				},
+				{
+					fixtures: []string{"testdata/fixtures/merge/extra.yaml"},
+					meta:     metadata.Metadata{Version: 1},
+				},
+			},
+			wantErr: "unsupported schema version 1",
+		},
+		{
+			name: "source still opened",
+			srcs: []src{
+				{
+					fixtures: []string{"testdata/fixtures/merge/upstream.yaml"},
+					meta:     metadata.Metadata{Version: db.SchemaVersion},
+					keepOpen: true,
+				},
+			},
+			wantErr: "timeout",
+		},
+		{
+			name:    "no database",
+			wantErr: "no database to merge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var srcDirs []string
+			for _, s := range tt.srcs {
+				dir := dbtest.InitDB(t, s.fixtures)
+				if s.keepOpen {
+					defer db.Close()
+				} else {
+					require.NoError(t, db.Close())
+				}
+				require.NoError(t, metadata.NewClient(dir).Update(s.meta))
+				srcDirs = append(srcDirs, dir)
+			}
+
+			dstDir := t.TempDir()
+			err := vulndb.Merge(dstDir, srcDirs...)
+			if tt.wantErr != "" {
+				require.NotNil(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				// A failed merge must not leave a partial database behind
+				assert.NoFileExists(t, db.Path(dstDir))
+				return
+			}
+			require.NoError(t, err)
+
+			gotMeta, err := metadata.NewClient(dstDir).Get()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMeta, gotMeta)
+
+			dbPath := db.Path(dstDir)
+			for _, want := range tt.wantValues {
+				dbtest.JSONEq(t, dbPath, want.key, want.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package vulndb
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/db"
+)
+
+// checkRegression compares the number of records in each bucket with the previous database
+// and reports buckets that lost more than the allowed fraction, e.g. because of a truncated feed.
+// Buckets missing from the new database count as a full drop.
+func (t TrivyDB) checkRegression() error {
+	prev, err := db.CountFile(db.Path(t.prevCacheDir))
+	if err != nil {
+		return xerrors.Errorf("failed to count records of the previous database: %w", err)
+	}
+	cur, err := t.dbc.Count()
+	if err != nil {
+		return xerrors.Errorf("failed to count records: %w", err)
+	}
+
+	records := map[string]int{}
+	for _, b := range cur {
+		records[b.Name] = b.Records
+	}
+
+	var drops []string
+	for _, b := range prev {
+		if b.Records == 0 {
+			continue
+		}
+		n := records[b.Name]
+		if float64(b.Records-n)/float64(b.Records) > t.maxRecordDrop {
+			drops = append(drops, fmt.Sprintf("%s: %d -> %d", b.Name, b.Records, n))
+		}
+	}
+	if len(drops) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("the number of records dropped by more than %.0f%% from the previous database: %s",
+		t.maxRecordDrop*100, strings.Join(drops, ", "))
+	if t.strictRecordDrop {
+		return xerrors.New(msg)
+	}
+	log.Println(msg)
+	return nil
+}
@@ -20,11 +20,27 @@ var (
 )
 
 type Vulnerability struct {
-	dbc db.Operation
+	dbc              db.Operation
+	severityFromCVSS bool
 }
 
-func New(dbc db.Operation) Vulnerability {
-	return Vulnerability{dbc: dbc}
+type Option func(*Vulnerability)
+
+// WithSeverityFromCVSS derives vendor severities from CVSS scores when they are available,
+// falling back to the vendor-provided severity labels.
+// By default, the labels take precedence over the scores.
+func WithSeverityFromCVSS() Option {
+	return func(v *Vulnerability) {
+		v.severityFromCVSS = true
+	}
+}
+
+func New(dbc db.Operation, opts ...Option) Vulnerability {
+	v := Vulnerability{dbc: dbc}
+	for _, opt := range opts {
+		opt(&v)
+	}
+	return v
 }
 
 func (v Vulnerability) GetDetails(vulnID string) map[types.SourceID]types.VulnerabilityDetail {
@@ -42,13 +58,13 @@ func (Vulnerability) IsRejected(details map[types.SourceID]types.VulnerabilityDe
 	return getRejectedStatus(details)
 }
 
-func (Vulnerability) Normalize(details map[types.SourceID]types.VulnerabilityDetail) types.Vulnerability {
+func (v Vulnerability) Normalize(details map[types.SourceID]types.VulnerabilityDetail) types.Vulnerability {
 	return types.Vulnerability{
 		Title:            getTitle(details),
 		Description:      getDescription(details),
 		Severity:         getSeverity(details).String(), // TODO: We have to keep this key until we deprecate
 		CweIDs:           getCweIDs(details),
-		VendorSeverity:   getVendorSeverity(details),
+		VendorSeverity:   getVendorSeverity(details, v.severityFromCVSS),
 		CVSS:             getCVSS(details),
 		References:       getReferences(details),
 		PublishedDate:    details[NVD].PublishedDate,
@@ -72,10 +88,14 @@ func getCVSS(details map[types.SourceID]types.VulnerabilityDetail) types.VendorC
 	return vc
 }
 
-func getVendorSeverity(details map[types.SourceID]types.VulnerabilityDetail) types.VendorSeverity {
+func getVendorSeverity(details map[types.SourceID]types.VulnerabilityDetail, fromCVSS bool) types.VendorSeverity {
 	vs := make(types.VendorSeverity)
 	for vendor, detail := range details {
 		switch {
+		case fromCVSS && detail.CvssScoreV3 > 0:
+			vs[vendor] = scoreToSeverity(detail.CvssScoreV3)
+		case fromCVSS && detail.CvssScore > 0:
+			vs[vendor] = scoreToSeverity(detail.CvssScore)
 		case detail.SeverityV3 != types.SeverityUnknown:
 			vs[vendor] = detail.SeverityV3
 		case detail.Severity != types.SeverityUnknown:
@@ -135,6 +135,7 @@ func TestIsRejected(t *testing.T) {
 func TestNormalize(t *testing.T) {
 	testCases := []struct {
 		name    string
+		opts    []vulnerability.Option
 		details map[types.SourceID]types.VulnerabilityDetail
 		want    types.Vulnerability
 	}{
@@ -241,6 +242,43 @@ func TestNormalize(t *testing.T) {
 				References: []string{"http://foo-bar.com/baz"},
 			},
 		},
+		{
+			name: "severity from CVSS",
+			opts: []vulnerability.Option{vulnerability.WithSeverityFromCVSS()},
+			details: map[types.SourceID]types.VulnerabilityDetail{
+				vulnerability.RedHat: {
+					CvssScoreV3:  5.6,
+					CvssVectorV3: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+					SeverityV3:   types.SeverityCritical,
+				},
+				vulnerability.Ubuntu: {
+					CvssScore:  7.5,
+					CvssVector: "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+					Severity:   types.SeverityLow,
+				},
+				vulnerability.Debian: {
+					Severity: types.SeverityLow,
+				},
+			},
+			want: types.Vulnerability{
+				Severity: types.SeverityMedium.String(), // from Red Hat
+				VendorSeverity: types.VendorSeverity{
+					vulnerability.RedHat: types.SeverityMedium,
+					vulnerability.Ubuntu: types.SeverityHigh,
+					vulnerability.Debian: types.SeverityLow,
+				},
+				CVSS: types.VendorCVSS{
+					vulnerability.RedHat: types.CVSS{
+						V3Vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+						V3Score:  5.6,
+					},
+					vulnerability.Ubuntu: types.CVSS{
+						V2Vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+						V2Score:  7.5,
+					},
+				},
+			},
+		},
 		{
 			name: "happy path, classifications for ubuntu and nodejs with variety vectors but no scores",
 			details: map[types.SourceID]types.VulnerabilityDetail{
@@ -271,7 +309,7 @@ func TestNormalize(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := vulnerability.New(nil).Normalize(tc.details)
+			got := vulnerability.New(nil, tc.opts...).Normalize(tc.details)
 			assert.Equal(t, tc.want, got)
 		})
 	}
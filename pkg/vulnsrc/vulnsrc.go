@@ -63,4 +63,43 @@ var (
 		ghsa.NewVulnSrc(),
 		glad.NewVulnSrc(),
 	}
+
+	// registered holds data sources added via Register, in registration order
+	registered []registeredSrc
 )
+
+type registeredSrc struct {
+	id  types.SourceID
+	src VulnSrc
+}
+
+// Register adds a data source so that downstream forks can plug in their own sources
+// without patching All. A source registered with the ID of a built-in one replaces it.
+// Register is not safe for concurrent use and is meant to be called from init functions.
+func Register(id types.SourceID, src VulnSrc) {
+	for i, r := range registered {
+		if r.id == id {
+			registered[i].src = src
+			return
+		}
+	}
+	registered = append(registered, registeredSrc{id: id, src: src})
+}
+
+// Sources returns the IDs of all data sources and the sources keyed by them,
+// built-in ones first followed by registered ones.
+func Sources() ([]types.SourceID, map[types.SourceID]VulnSrc) {
+	var ids []types.SourceID
+	srcs := map[types.SourceID]VulnSrc{}
+	for _, src := range All {
+		ids = append(ids, src.Name())
+		srcs[src.Name()] = src
+	}
+	for _, r := range registered {
+		if _, ok := srcs[r.id]; !ok {
+			ids = append(ids, r.id)
+		}
+		srcs[r.id] = r.src
+	}
+	return ids, srcs
+}
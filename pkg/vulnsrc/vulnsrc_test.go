@@ -0,0 +1,38 @@
+package vulnsrc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+)
+
+type fakeVulnSrc struct {
+	name types.SourceID
+}
+
+func (f fakeVulnSrc) Name() types.SourceID { return f.name }
+
+func (f fakeVulnSrc) Update(string) error { return nil }
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() { registered = nil })
+
+	private := fakeVulnSrc{name: "private"}
+	Register("private", fakeVulnSrc{name: "old"})
+	Register("private", private)
+
+	debian := fakeVulnSrc{name: vulnerability.Debian}
+	Register(vulnerability.Debian, debian)
+
+	ids, srcs := Sources()
+	require.Len(t, ids, len(All)+1)
+	assert.Equal(t, types.SourceID("private"), ids[len(ids)-1])
+	assert.Len(t, srcs, len(ids))
+	assert.Equal(t, private, srcs["private"])
+	assert.Equal(t, debian, srcs[vulnerability.Debian])
+	assert.Equal(t, All[0], srcs[All[0].Name()])
+}